@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// serveAdmin inicia o servidor HTTP administrativo, usado para inspecionar
+// o estado interno do servidor gRPC (contadores do cache, etc.).
+func serveAdmin(addr string, mux *http.ServeMux) {
+	log.Printf("Endpoints administrativos em http://%s/admin/", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("Erro no servidor administrativo: %v", err)
+	}
+}
+
+// writeAdminJSON responde a requisição administrativa com o valor codificado em JSON
+func writeAdminJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// handleCacheStats retorna os contadores de acertos e falhas do cache
+func handleCacheStats(cache *cachedProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cache == nil {
+			http.Error(w, "Cache desabilitado", http.StatusNotFound)
+			return
+		}
+		writeAdminJSON(w, cache.Stats())
+	}
+}
+
+// handleProviderStats retorna a saúde e o estado do circuit breaker de cada fornecedor da cadeia
+func handleProviderStats(chain *chainProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if chain == nil {
+			http.Error(w, "Apenas um fornecedor configurado", http.StatusNotFound)
+			return
+		}
+		writeAdminJSON(w, chain.Stats())
+	}
+}
+
+// handleUpstreamStats retorna as tentativas, falhas e mudanças do circuit breaker de cada cliente HTTP externo
+func handleUpstreamStats(upstreams *upstreamSet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, upstreams.Stats())
+	}
+}
+
+// handleQuotaStats retorna a cota restante de cada chave de API de fornecedor externo
+func handleQuotaStats(upstreams *upstreamSet) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, upstreams.QuotaStats())
+	}
+}
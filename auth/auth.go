@@ -0,0 +1,171 @@
+// Package auth autentica as requisições ao gateway HTTP e ao servidor gRPC por token bearer.
+//
+// São aceitos dois tipos de token: chaves de API estáticas, lidas do arquivo de chaves, e JWTs
+// assinados (RS256 ou ES256), verificados com as chaves públicas de um arquivo JWKS local.
+// Cada credencial carrega escopos, que liberam grupos de rotas (ex.: previsão ou histórico).
+package auth
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"grpc-client/config"
+)
+
+// Escopos reconhecidos
+const (
+	// Clima atual (simples, em lote e em tempo real) e busca de cidades
+	ScopeWeather = "weather"
+	// Previsão de vários dias
+	ScopeForecast = "forecast"
+	// Histórico de observações
+	ScopeHistory = "history"
+)
+
+// allScopes é concedido às chaves de API cadastradas sem escopos
+var allScopes = []string{ScopeWeather, ScopeForecast, ScopeHistory}
+
+// Tolerância na verificação de exp e nbf dos JWTs, para diferenças de relógio
+const clockLeeway = time.Minute
+
+var (
+	// Nenhum token foi enviado
+	ErrMissingToken = errors.New("token de acesso ausente")
+	// O token não é uma chave cadastrada nem um JWT válido
+	ErrInvalidToken = errors.New("token de acesso inválido")
+)
+
+// Principal é a credencial autenticada
+type Principal struct {
+	Subject string // "key:<prefixo do hash>" para chaves de API; o "sub" para JWTs
+	Scopes  map[string]bool
+}
+
+// HasScope indica se a credencial libera o escopo
+func (p *Principal) HasScope(scope string) bool {
+	return p.Scopes[scope]
+}
+
+// Authenticator valida os tokens recebidos
+type Authenticator struct {
+	keys     map[string]*Principal // Indexadas pelo hash SHA-256 da chave
+	jwks     *KeySet               // nil não aceita JWTs
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// New cria o autenticador. keys associa cada chave de API aos seus escopos
+// (sem escopos, todos); issuer e audience vazios não são verificados nos JWTs.
+func New(keys map[string][]string, jwks *KeySet, issuer, audience string) *Authenticator {
+	a := &Authenticator{
+		keys:     make(map[string]*Principal, len(keys)),
+		jwks:     jwks,
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}
+	for key, scopes := range keys {
+		if len(scopes) == 0 {
+			scopes = allScopes
+		}
+		hash := hashKey(key)
+		a.keys[hash] = &Principal{Subject: "key:" + hash[:16], Scopes: scopeSet(scopes)}
+	}
+	return a
+}
+
+// NewFromConfig cria o autenticador com as opções de configuração e o arquivo de chaves de API
+func NewFromConfig(cfg config.AuthConfig, keysFile string) (*Authenticator, error) {
+	keys, err := LoadKeys(keysFile)
+	if err != nil {
+		return nil, err
+	}
+	var jwks *KeySet
+	if cfg.JWKSFile != "" {
+		if jwks, err = LoadKeySet(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+	return New(keys, jwks, cfg.JWTIssuer, cfg.JWTAudience), nil
+}
+
+// Authenticate valida o token, que pode ser uma chave de API ou um JWT
+func (a *Authenticator) Authenticate(token string) (*Principal, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+	if p, ok := a.keys[hashKey(token)]; ok {
+		return p, nil
+	}
+	if a.jwks != nil && strings.Count(token, ".") == 2 {
+		return a.verifyJWT(token)
+	}
+	return nil, ErrInvalidToken
+}
+
+// LoadKeys lê o arquivo de chaves de API. Cada linha tem a chave e, opcionalmente, os
+// escopos separados por vírgula (ex.: "abc123 weather,forecast"); sem escopos, a chave
+// libera todos. Linhas vazias e iniciadas por # são ignoradas.
+func LoadKeys(path string) (map[string][]string, error) {
+	keys := make(map[string][]string)
+	if path == "" {
+		return keys, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir arquivo de chaves de API: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		var scopes []string
+		if len(fields) > 1 {
+			for _, s := range strings.Split(fields[1], ",") {
+				if !isKnownScope(s) {
+					return nil, fmt.Errorf("escopo desconhecido na linha %d do arquivo de chaves: %q", line, s)
+				}
+				scopes = append(scopes, s)
+			}
+		}
+		keys[fields[0]] = scopes
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao ler arquivo de chaves de API: %v", err)
+	}
+	return keys, nil
+}
+
+func isKnownScope(s string) bool {
+	for _, known := range allScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeSet(scopes []string) map[string]bool {
+	set := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		set[s] = true
+	}
+	return set
+}
+
+// hashKey evita guardar e comparar as chaves em claro
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
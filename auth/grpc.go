@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Metadado gRPC com o token ("Bearer <token>")
+const authorizationMD = "authorization"
+
+// Prefixo dos métodos do health check, que não exigem token
+const healthMethodPrefix = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor exige um token válido nas chamadas simples. scopes associa cada
+// método (nome completo) ao escopo exigido; métodos fora do mapa exigem apenas um token válido.
+// As recusas retornam Unauthenticated ou PermissionDenied com ErrorInfo no domínio informado.
+func (a *Authenticator) UnaryServerInterceptor(scopes map[string]string, domain string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod, scopes[info.FullMethod], domain); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor é a versão de UnaryServerInterceptor para streams
+func (a *Authenticator) StreamServerInterceptor(scopes map[string]string, domain string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod, scopes[info.FullMethod], domain); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize valida o token dos metadados da chamada e o escopo do método
+func (a *Authenticator) authorize(ctx context.Context, method, scope, domain string) error {
+	if strings.HasPrefix(method, healthMethodPrefix) {
+		return nil
+	}
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(authorizationMD); len(values) > 0 {
+			token = BearerToken(values[0])
+		}
+	}
+
+	p, err := a.Authenticate(token)
+	if err != nil {
+		if !errors.Is(err, ErrMissingToken) {
+			log.Printf("Token recusado em %s: %v", method, err)
+		}
+		return authStatus(codes.Unauthenticated, "UNAUTHENTICATED", "token de acesso ausente, inválido ou expirado", domain)
+	}
+	if scope != "" && !p.HasScope(scope) {
+		return authStatus(codes.PermissionDenied, "INSUFFICIENT_SCOPE", "token sem o escopo "+scope, domain)
+	}
+	return nil
+}
+
+func authStatus(code codes.Code, reason, message, domain string) error {
+	st := status.New(code, message)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: domain})
+	if err != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// UnaryClientInterceptor repassa ao servidor gRPC o token guardado no contexto (WithToken).
+// Usado pelo gateway HTTP, para que o servidor gRPC autorize o cliente final.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(forwardToken(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor é a versão de UnaryClientInterceptor para streams
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(forwardToken(ctx), desc, cc, method, opts...)
+	}
+}
+
+func forwardToken(ctx context.Context) context.Context {
+	token, ok := TokenFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authorizationMD, "Bearer "+token)
+}
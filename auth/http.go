@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type tokenKey struct{}
+
+// WithToken guarda o token no contexto, para ser repassado ao servidor gRPC
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext retorna o token guardado por WithToken
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey{}).(string)
+	return token, ok && token != ""
+}
+
+// BearerToken extrai o token do valor de um cabeçalho "Authorization: Bearer <token>"
+func BearerToken(authorization string) string {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// requestToken obtém o token do cabeçalho Authorization ou, para o EventSource do navegador,
+// que não envia cabeçalhos, do parâmetro access_token (RFC 6750)
+func requestToken(r *http.Request) string {
+	if token := BearerToken(r.Header.Get("Authorization")); token != "" {
+		return token
+	}
+	return r.URL.Query().Get("access_token")
+}
+
+// Forward apenas guarda o token da requisição no contexto, sem validá-lo, para que o
+// servidor gRPC o verifique quando o gateway não exige autenticação própria
+func Forward(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := requestToken(r); token != "" {
+			r = r.WithContext(WithToken(r.Context(), token))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Middleware exige um token com o escopo informado. Sem token ou com um token inválido, a
+// resposta é 401; com um token válido sem o escopo, 403. As recusas levam o cabeçalho
+// WWW-Authenticate e são respondidas por reject. O token fica no contexto da requisição.
+func (a *Authenticator) Middleware(scope string, next http.Handler, reject func(w http.ResponseWriter, r *http.Request, status int, message string)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		p, err := a.Authenticate(token)
+		if err != nil {
+			if errors.Is(err, ErrMissingToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="weather"`)
+				reject(w, r, http.StatusUnauthorized, "Token de acesso ausente")
+				return
+			}
+			log.Printf("Token recusado em %s: %v", r.URL.Path, err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="weather", error="invalid_token"`)
+			reject(w, r, http.StatusUnauthorized, "Token de acesso inválido ou expirado")
+			return
+		}
+		if !p.HasScope(scope) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="weather", error="insufficient_scope", scope="`+scope+`"`)
+			reject(w, r, http.StatusForbidden, "Token sem o escopo "+scope)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithToken(r.Context(), token)))
+	})
+}
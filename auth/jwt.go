@@ -0,0 +1,231 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// KeySet são as chaves públicas de um arquivo JWKS (RFC 7517)
+type KeySet struct {
+	keys []publicKey
+}
+
+// publicKey é uma chave do JWKS já convertida para verificação
+type publicKey struct {
+	kid string
+	alg string // RS256 ou ES256
+	key crypto.PublicKey
+}
+
+// jwk é uma chave no formato JSON do JWKS
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// LoadKeySet lê o arquivo JWKS. São aceitas chaves RSA (RS256) e EC P-256 (ES256) de assinatura.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler JWKS: %v", err)
+	}
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("JWKS inválido: %v", err)
+	}
+
+	set := &KeySet{}
+	for i, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pk, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("chave %d (kid %q) do JWKS inválida: %v", i, k.Kid, err)
+		}
+		set.keys = append(set.keys, pk)
+	}
+	if len(set.keys) == 0 {
+		return nil, fmt.Errorf("JWKS sem chaves de assinatura")
+	}
+	return set, nil
+}
+
+func (k jwk) publicKey() (publicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return publicKey{}, fmt.Errorf("n: %v", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return publicKey{}, fmt.Errorf("e inválido")
+		}
+		return publicKey{kid: k.Kid, alg: "RS256", key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return publicKey{}, fmt.Errorf("curva não suportada: %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return publicKey{}, fmt.Errorf("x: %v", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return publicKey{}, fmt.Errorf("y: %v", err)
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		if !pub.Curve.IsOnCurve(x, y) {
+			return publicKey{}, fmt.Errorf("ponto fora da curva")
+		}
+		return publicKey{kid: k.Kid, alg: "ES256", key: pub}, nil
+	default:
+		return publicKey{}, fmt.Errorf("tipo de chave não suportado: %q", k.Kty)
+	}
+}
+
+// find retorna a chave do kid informado. Sem kid, só há escolha se houver uma única chave do algoritmo.
+func (s *KeySet) find(kid, alg string) (publicKey, bool) {
+	var match []publicKey
+	for _, k := range s.keys {
+		if k.alg == alg && (kid == "" || k.kid == kid) {
+			match = append(match, k)
+		}
+	}
+	if len(match) != 1 {
+		return publicKey{}, false
+	}
+	return match[0], true
+}
+
+// jwtClaims são as claims usadas na autorização. Os escopos vêm de "scope"
+// (separados por espaço, como no OAuth 2.0) ou de "scp" (lista).
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *int64          `json:"exp"`
+	NotBefore *int64          `json:"nbf"`
+	Scope     string          `json:"scope"`
+	Scp       []string        `json:"scp"`
+}
+
+// hasAudience indica se aud (texto ou lista) contém a audiência esperada
+func (c jwtClaims) hasAudience(want string) bool {
+	var one string
+	if json.Unmarshal(c.Audience, &one) == nil {
+		return one == want
+	}
+	var many []string
+	if json.Unmarshal(c.Audience, &many) == nil {
+		for _, aud := range many {
+			if aud == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// verifyJWT confere a assinatura e as claims do JWT. O exp é obrigatório.
+func (a *Authenticator) verifyJWT(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: cabeçalho do JWT: %v", ErrInvalidToken, err)
+	}
+	key, ok := a.jwks.find(header.Kid, header.Alg)
+	if !ok {
+		return nil, fmt.Errorf("%w: nenhuma chave do JWKS para kid %q e alg %q", ErrInvalidToken, header.Kid, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: assinatura mal codificada", ErrInvalidToken)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !verifySignature(key, hash[:], sig) {
+		return nil, fmt.Errorf("%w: assinatura não confere", ErrInvalidToken)
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims do JWT: %v", ErrInvalidToken, err)
+	}
+	now := a.now()
+	if claims.ExpiresAt == nil {
+		return nil, fmt.Errorf("%w: JWT sem exp", ErrInvalidToken)
+	}
+	if now.After(time.Unix(*claims.ExpiresAt, 0).Add(clockLeeway)) {
+		return nil, fmt.Errorf("%w: JWT expirado", ErrInvalidToken)
+	}
+	if claims.NotBefore != nil && now.Add(clockLeeway).Before(time.Unix(*claims.NotBefore, 0)) {
+		return nil, fmt.Errorf("%w: JWT ainda não é válido", ErrInvalidToken)
+	}
+	if a.issuer != "" && claims.Issuer != a.issuer {
+		return nil, fmt.Errorf("%w: emissor %q não aceito", ErrInvalidToken, claims.Issuer)
+	}
+	if a.audience != "" && !claims.hasAudience(a.audience) {
+		return nil, fmt.Errorf("%w: JWT não é destinado a %q", ErrInvalidToken, a.audience)
+	}
+
+	scopes := append(strings.Fields(claims.Scope), claims.Scp...)
+	return &Principal{Subject: claims.Subject, Scopes: scopeSet(scopes)}, nil
+}
+
+func verifySignature(key publicKey, hash, sig []byte) bool {
+	switch pub := key.key.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, crypto.SHA256, hash, sig) == nil
+	case *ecdsa.PublicKey:
+		// Em JWS, a assinatura ECDSA é r||s com 32 bytes cada
+		if len(sig) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(pub, hash, r, s)
+	default:
+		return false
+	}
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("valor base64url inválido")
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,305 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Instante fixo usado como relógio do autenticador nos testes
+var testNow = time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+
+const (
+	testIssuer   = "https://auth.example.com/"
+	testAudience = "weather-api"
+)
+
+// testKeys são as chaves privadas cujas públicas estão no JWKS dos testes
+type testKeys struct {
+	rsa      *rsa.PrivateKey
+	ec       *ecdsa.PrivateKey
+	otherRSA *rsa.PrivateKey // fora do JWKS
+}
+
+// newTestAuthenticator gera as chaves, grava o JWKS em um arquivo temporário e o carrega
+func newTestAuthenticator(t *testing.T) (*Authenticator, testKeys) {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	jwks := map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "use": "sig", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		// Chaves de cifragem são ignoradas
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": b64(otherRSA.N.Bytes()), "e": b64(big.NewInt(int64(otherRSA.E)).Bytes())},
+	}}
+	data, _ := json.Marshal(jwks)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := ioutil.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := LoadKeySet(path)
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+	a := New(map[string][]string{"chave-estatica": {ScopeWeather}}, set, testIssuer, testAudience)
+	a.now = func() time.Time { return testNow }
+	return a, testKeys{rsa: rsaKey, ec: ecKey, otherRSA: otherRSA}
+}
+
+// signJWT monta o token com o cabeçalho e as claims informados, assinado por key:
+// *rsa.PrivateKey (RS256), *ecdsa.PrivateKey (ES256), []byte (HMAC-SHA256) ou nil (sem assinatura)
+func signJWT(t *testing.T, header, claims map[string]interface{}, key interface{}) string {
+	t.Helper()
+	enc := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := enc(header) + "." + enc(claims)
+	hash := sha256.Sum256([]byte(signingInput))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, hash[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, hash[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signingInput))
+		sig = mac.Sum(nil)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// validClaims retorna claims aceitas pelo autenticador dos testes, com as alterações informadas
+func validClaims(changes map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub":   "user-42",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"nbf":   testNow.Add(-time.Minute).Unix(),
+		"scope": "weather forecast",
+	}
+	for k, v := range changes {
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+	}
+	return claims
+}
+
+func TestAuthenticateJWT(t *testing.T) {
+	a, keys := newTestAuthenticator(t)
+	rs256 := map[string]interface{}{"alg": "RS256", "kid": "rsa-1", "typ": "JWT"}
+	es256 := map[string]interface{}{"alg": "ES256", "kid": "ec-1", "typ": "JWT"}
+
+	// Chave pública RSA em PEM, usada como segredo HMAC no ataque de confusão de algoritmo
+	der, _ := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantScopes []string // escopos esperados quando o token é aceito
+		wantErr    bool
+	}{
+		{"RS256 válido", signJWT(t, rs256, validClaims(nil), keys.rsa), []string{ScopeWeather, ScopeForecast}, false},
+		{"ES256 válido", signJWT(t, es256, validClaims(nil), keys.ec), []string{ScopeWeather, ScopeForecast}, false},
+		{"escopos em scp", signJWT(t, rs256, validClaims(map[string]interface{}{"scope": nil, "scp": []string{ScopeHistory}}), keys.rsa), []string{ScopeHistory}, false},
+		{"audiência em lista", signJWT(t, rs256, validClaims(map[string]interface{}{"aud": []string{"outra", testAudience}}), keys.rsa), []string{ScopeWeather, ScopeForecast}, false},
+		{"expirado dentro da tolerância", signJWT(t, rs256, validClaims(map[string]interface{}{"exp": testNow.Add(-30 * time.Second).Unix()}), keys.rsa), []string{ScopeWeather, ScopeForecast}, false},
+		{"expirado", signJWT(t, rs256, validClaims(map[string]interface{}{"exp": testNow.Add(-2 * time.Minute).Unix()}), keys.rsa), nil, true},
+		{"sem exp", signJWT(t, rs256, validClaims(map[string]interface{}{"exp": nil}), keys.rsa), nil, true},
+		{"nbf no futuro", signJWT(t, rs256, validClaims(map[string]interface{}{"nbf": testNow.Add(5 * time.Minute).Unix()}), keys.rsa), nil, true},
+		{"audiência errada", signJWT(t, rs256, validClaims(map[string]interface{}{"aud": "outra-api"}), keys.rsa), nil, true},
+		{"sem audiência", signJWT(t, rs256, validClaims(map[string]interface{}{"aud": nil}), keys.rsa), nil, true},
+		{"emissor errado", signJWT(t, rs256, validClaims(map[string]interface{}{"iss": "https://evil.example.com/"}), keys.rsa), nil, true},
+		{"alg none", signJWT(t, map[string]interface{}{"alg": "none", "kid": "rsa-1"}, validClaims(nil), nil), nil, true},
+		{"HS256 com a chave pública RSA como segredo", signJWT(t, map[string]interface{}{"alg": "HS256", "kid": "rsa-1"}, validClaims(nil), rsaPEM), nil, true},
+		{"HS256 com o módulo RSA como segredo", signJWT(t, map[string]interface{}{"alg": "HS256", "kid": "rsa-1"}, validClaims(nil), keys.rsa.N.Bytes()), nil, true},
+		{"kid desconhecido", signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-2"}, validClaims(nil), keys.rsa), nil, true},
+		{"kid de chave de cifragem", signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "enc-1"}, validClaims(nil), keys.otherRSA), nil, true},
+		{"kid de outra chave com alg trocado", signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "ec-1"}, validClaims(nil), keys.rsa), nil, true},
+		{"assinado por chave fora do JWKS", signJWT(t, rs256, validClaims(nil), keys.otherRSA), nil, true},
+		{"claims alteradas depois da assinatura", tamperClaims(t, signJWT(t, rs256, validClaims(nil), keys.rsa), validClaims(map[string]interface{}{"scope": "weather forecast history"})), nil, true},
+		{"assinatura ES256 truncada", truncateSignature(signJWT(t, es256, validClaims(nil), keys.ec)), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := a.Authenticate(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Authenticate = %+v, %v, quer ErrInvalidToken", p, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if p.Subject != "user-42" {
+				t.Errorf("Subject = %q, quer user-42", p.Subject)
+			}
+			if len(p.Scopes) != len(tt.wantScopes) {
+				t.Errorf("escopos = %v, quer %v", p.Scopes, tt.wantScopes)
+			}
+			for _, s := range tt.wantScopes {
+				if !p.HasScope(s) {
+					t.Errorf("escopos = %v, quer %v", p.Scopes, tt.wantScopes)
+				}
+			}
+		})
+	}
+}
+
+// tamperClaims troca as claims do token mantendo o cabeçalho e a assinatura originais
+func tamperClaims(t *testing.T, token string, claims map[string]interface{}) string {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	data, _ := json.Marshal(claims)
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(data) + "." + parts[2]
+}
+
+// truncateSignature remove o último byte da assinatura do token
+func truncateSignature(token string) string {
+	parts := strings.Split(token, ".")
+	sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	return parts[0] + "." + parts[1] + "." + base64.RawURLEncoding.EncodeToString(sig[:len(sig)-1])
+}
+
+func TestAuthenticateWithoutJWKS(t *testing.T) {
+	a, keys := newTestAuthenticator(t)
+	token := signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, validClaims(nil), keys.rsa)
+
+	// Sem JWKS, nenhum JWT é aceito, mesmo bem assinado
+	withoutJWKS := New(nil, nil, testIssuer, testAudience)
+	if _, err := withoutJWKS.Authenticate(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate sem JWKS = %v, quer ErrInvalidToken", err)
+	}
+	if _, err := a.Authenticate(""); !errors.Is(err, ErrMissingToken) {
+		t.Errorf("Authenticate sem token = %v, quer ErrMissingToken", err)
+	}
+	if _, err := a.Authenticate("chave-desconhecida"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate com chave desconhecida = %v, quer ErrInvalidToken", err)
+	}
+}
+
+func TestMiddlewareScopes(t *testing.T) {
+	a, keys := newTestAuthenticator(t)
+	weatherOnly := signJWT(t, map[string]interface{}{"alg": "ES256", "kid": "ec-1"}, validClaims(map[string]interface{}{"scope": "weather"}), keys.ec)
+	expired := signJWT(t, map[string]interface{}{"alg": "ES256", "kid": "ec-1"}, validClaims(map[string]interface{}{"exp": testNow.Add(-time.Hour).Unix()}), keys.ec)
+
+	tests := []struct {
+		name       string
+		scope      string
+		token      string
+		wantStatus int
+		wantAuth   string // trecho esperado no WWW-Authenticate
+	}{
+		{"JWT com o escopo", ScopeWeather, weatherOnly, http.StatusOK, ""},
+		{"JWT sem o escopo", ScopeHistory, weatherOnly, http.StatusForbidden, `error="insufficient_scope", scope="history"`},
+		{"chave estática sem o escopo", ScopeForecast, "chave-estatica", http.StatusForbidden, `error="insufficient_scope"`},
+		{"JWT expirado", ScopeWeather, expired, http.StatusUnauthorized, `error="invalid_token"`},
+		{"sem token", ScopeWeather, "", http.StatusUnauthorized, `Bearer realm="weather"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := a.Middleware(tt.scope, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if token, ok := TokenFromContext(r.Context()); !ok || token != tt.token {
+					t.Errorf("token no contexto = %q, quer o da requisição", token)
+				}
+				w.WriteHeader(http.StatusOK)
+			}), func(w http.ResponseWriter, r *http.Request, status int, message string) {
+				w.WriteHeader(status)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, quer %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); !strings.Contains(got, tt.wantAuth) {
+				t.Errorf("WWW-Authenticate = %q, quer %q", got, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestAuthorizeGRPCScopes(t *testing.T) {
+	a, keys := newTestAuthenticator(t)
+	weatherOnly := signJWT(t, map[string]interface{}{"alg": "RS256", "kid": "rsa-1"}, validClaims(map[string]interface{}{"scope": "weather"}), keys.rsa)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationMD, "Bearer "+weatherOnly))
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		scope  string
+		want   codes.Code
+	}{
+		{"com o escopo", ctx, "/weather.WeatherService/GetWeather", ScopeWeather, codes.OK},
+		{"sem o escopo", ctx, "/weather.WeatherService/GetHistory", ScopeHistory, codes.PermissionDenied},
+		{"sem token", context.Background(), "/weather.WeatherService/GetWeather", ScopeWeather, codes.Unauthenticated},
+		{"health check sem token", context.Background(), "/grpc.health.v1.Health/Check", "", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.authorize(tt.ctx, tt.method, tt.scope, "weather.grpc-client")
+			if code := status.Code(err); code != tt.want {
+				t.Errorf("authorize = %v, quer %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	pb "grpc-client/web"
+
+	"google.golang.org/grpc/status"
+)
+
+// Quantidade máxima de cidades aceitas em um único lote
+const maxBatchCities = 100
+
+// runBatch busca o clima de cada cidade usando no máximo "workers" consultas simultâneas.
+// Os resultados mantêm a ordem das cidades pedidas, e a falha de uma cidade
+// é registrada no próprio resultado sem interromper as demais.
+func runBatch(ctx context.Context, cities []string, workers int, fetch func(context.Context, string) (*pb.WeatherResponse, error)) []*pb.WeatherBatchResult {
+	results := make([]*pb.WeatherBatchResult, len(cities))
+	if workers > len(cities) {
+		workers = len(cities)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				result := &pb.WeatherBatchResult{City: cities[idx]}
+				weather, err := fetch(ctx, cities[idx])
+				if err != nil {
+					// Apenas a mensagem do status gRPC, sem o prefixo "rpc error: code = ..."
+					result.Error = status.Convert(err).Message()
+				} else {
+					result.Weather = weather
+				}
+				results[idx] = result
+			}
+		}()
+	}
+
+	for idx := range cities {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// validateBatch verifica o tamanho do lote e se todas as cidades foram informadas
+func validateBatch(cities []string) error {
+	if len(cities) == 0 {
+		return &invalidArgumentError{Field: "cities", Description: "nenhuma cidade informada"}
+	}
+	if len(cities) > maxBatchCities {
+		return &invalidArgumentError{
+			Field:       "cities",
+			Description: fmt.Sprintf("lote com %d cidades excede o limite de %d", len(cities), maxBatchCities),
+		}
+	}
+	for i, city := range cities {
+		if strings.TrimSpace(city) == "" {
+			return &invalidArgumentError{Field: fmt.Sprintf("cities[%d]", i), Description: "cidade vazia"}
+		}
+	}
+	return nil
+}
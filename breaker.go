@@ -0,0 +1,132 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState é o estado de um circuit breaker
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // chamadas liberadas
+	breakerOpen                         // chamadas bloqueadas até o fim da espera
+	breakerHalfOpen                     // uma chamada de teste liberada
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// circuitBreaker interrompe as chamadas a um fornecedor depois de threshold falhas
+// seguidas. Passado o cooldown, libera uma única chamada de teste: se ela funcionar,
+// o circuito volta a fechar; se falhar, abre novamente por mais um cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	// onChange, se definido, é chamado a cada mudança de estado (com o lock do breaker)
+	onChange func(from, to breakerState)
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// Allow indica se a chamada pode ser feita. Com o circuito aberto há mais que o
+// cooldown, a chamada liberada passa a ser a de teste.
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+		b.probing = true
+		return true
+	case breakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success registra uma chamada bem-sucedida, fechando o circuito
+func (b *circuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.setState(breakerClosed)
+	b.failures, b.probing = 0, false
+}
+
+// Failure registra uma falha, abrindo o circuito ao atingir o limite ou se a chamada de teste falhar
+func (b *circuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.setState(breakerOpen)
+		b.openedAt, b.probing = b.now(), false
+	}
+}
+
+// setState muda o estado do circuito, avisando onChange quando ele de fato muda
+func (b *circuitBreaker) setState(to breakerState) {
+	from := b.state
+	b.state = to
+	if from != to && b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
+
+// Abort libera a chamada de teste que terminou sem resultado (ex.: o cliente desistiu),
+// para que a próxima chamada possa testar o fornecedor
+func (b *circuitBreaker) Abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// Blocked indica se Allow recusaria uma chamada agora: circuito aberto ainda dentro do
+// cooldown, ou meio aberto com a chamada de teste em andamento
+func (b *circuitBreaker) Blocked() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		return b.now().Sub(b.openedAt) < b.cooldown
+	case breakerHalfOpen:
+		return b.probing
+	default:
+		return false
+	}
+}
+
+// State retorna o estado atual do circuito
+func (b *circuitBreaker) State() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestBreaker cria um breaker com relógio controlado pelo teste
+func newTestBreaker(threshold int, cooldown time.Duration) (*circuitBreaker, *time.Time) {
+	now := time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(threshold, cooldown)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+	for i := 0; i < 2; i++ {
+		if !b.Allow() {
+			t.Fatalf("chamada %d recusada antes do limite", i+1)
+		}
+		b.Failure()
+	}
+	if b.State() != breakerClosed {
+		t.Fatalf("estado = %s depois de 2 falhas, quer closed", b.State())
+	}
+
+	// Um sucesso zera a contagem de falhas seguidas
+	b.Allow()
+	b.Success()
+	for i := 0; i < 3; i++ {
+		b.Allow()
+		b.Failure()
+	}
+	if b.State() != breakerOpen || b.Allow() || !b.Blocked() {
+		t.Errorf("estado = %s depois de 3 falhas seguidas, quer open recusando chamadas", b.State())
+	}
+}
+
+func TestCircuitBreakerHalfOpenSingleProbe(t *testing.T) {
+	b, now := newTestBreaker(1, time.Minute)
+	b.Allow()
+	b.Failure()
+
+	*now = now.Add(59 * time.Second)
+	if b.Allow() {
+		t.Fatalf("chamada liberada antes do fim do cooldown")
+	}
+
+	// Passado o cooldown, só uma chamada de teste é liberada
+	*now = now.Add(time.Second)
+	if b.Blocked() {
+		t.Errorf("Blocked depois do cooldown, quer a vez da chamada de teste")
+	}
+	if !b.Allow() {
+		t.Fatalf("chamada de teste recusada depois do cooldown")
+	}
+	if b.State() != breakerHalfOpen {
+		t.Fatalf("estado = %s, quer half-open", b.State())
+	}
+	if b.Allow() || !b.Blocked() {
+		t.Errorf("segunda chamada liberada com a chamada de teste em andamento")
+	}
+
+	tests := []struct {
+		name   string
+		finish func()
+		want   breakerState
+		allow  bool // se a chamada seguinte é liberada
+	}{
+		{"teste abortado libera novo teste", b.Abort, breakerHalfOpen, true},
+		{"teste com falha reabre", b.Failure, breakerOpen, false},
+	}
+	for _, tt := range tests {
+		tt.finish()
+		if b.State() != tt.want {
+			t.Errorf("%s: estado = %s, quer %s", tt.name, b.State(), tt.want)
+		}
+		if got := b.Allow(); got != tt.allow {
+			t.Errorf("%s: Allow = %v, quer %v", tt.name, got, tt.allow)
+		}
+	}
+
+	// Reaberto pela chamada de teste, espera mais um cooldown inteiro; o teste seguinte fecha o circuito
+	*now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatalf("nova chamada de teste recusada depois do segundo cooldown")
+	}
+	b.Success()
+	if b.State() != breakerClosed || !b.Allow() || !b.Allow() {
+		t.Errorf("estado = %s depois do teste bem-sucedido, quer closed liberando chamadas", b.State())
+	}
+}
+
+func TestCircuitBreakerOnChange(t *testing.T) {
+	b, now := newTestBreaker(1, time.Second)
+	var changes []string
+	b.onChange = func(from, to breakerState) { changes = append(changes, from.String()+">"+to.String()) }
+
+	b.Allow()
+	b.Failure()
+	*now = now.Add(time.Second)
+	b.Allow()
+	b.Success()
+
+	want := []string{"closed>open", "open>half-open", "half-open>closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("mudanças = %v, quer %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("mudança %d = %s, quer %s", i, changes[i], want[i])
+		}
+	}
+}
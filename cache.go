@@ -0,0 +1,215 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cacheStats reúne os contadores do cache, expostos no endpoint administrativo
+type cacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Coalesced uint64 `json:"coalesced"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+// lruCache é um cache em memória com expiração (TTL) e limite de tamanho (LRU).
+// Buscas simultâneas pela mesma chave ausente são agrupadas: apenas a primeira
+// executa a carga, e as demais aguardam e recebem o mesmo resultado.
+type lruCache[V any] struct {
+	ttl  time.Duration
+	size int
+	now  func() time.Time
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+	calls map[string]*cacheCall[V]
+
+	hits, misses, coalesced, evictions atomic.Uint64
+}
+
+type cacheEntry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+// cacheCall representa uma carga em andamento para uma chave
+type cacheCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newLRUCache[V any](ttl time.Duration, size int) *lruCache[V] {
+	return &lruCache[V]{
+		ttl:   ttl,
+		size:  size,
+		now:   time.Now,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		calls: make(map[string]*cacheCall[V]),
+	}
+}
+
+// Tempo máximo de uma carga do cache. A carga não usa o prazo de quem a iniciou,
+// para que a desistência desse chamador não derrube os demais que aguardam.
+const cacheLoadTimeout = 30 * time.Second
+
+// Get retorna o valor em cache para a chave ou executa load para obtê-lo.
+// A carga roda em segundo plano com um contexto próprio (com os valores do contexto de quem
+// chegou primeiro e prazo cacheLoadTimeout); cada chamador pode desistir pelo próprio contexto.
+// Erros não são armazenados.
+func (c *lruCache[V]) Get(ctx context.Context, key string, load func(context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*cacheEntry[V])
+		if c.now().Before(entry.expires) {
+			c.ll.MoveToFront(el)
+			c.mu.Unlock()
+			c.hits.Add(1)
+			return entry.value, nil
+		}
+		c.removeElement(el)
+	}
+
+	call, ok := c.calls[key]
+	if ok {
+		c.coalesced.Add(1)
+	} else {
+		call = &cacheCall[V]{done: make(chan struct{})}
+		c.calls[key] = call
+		c.misses.Add(1)
+		go c.load(ctx, key, call, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// load executa a carga da chave e libera quem aguarda, mesmo que load entre em pânico
+func (c *lruCache[V]) load(ctx context.Context, key string, call *cacheCall[V], load func(context.Context) (V, error)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheLoadTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Pânico na carga do cache para %q: %v\n%s", key, r, debug.Stack())
+			var zero V
+			call.value, call.err = zero, fmt.Errorf("falha interna ao carregar %q", key)
+		}
+
+		c.mu.Lock()
+		delete(c.calls, key)
+		if call.err == nil {
+			c.add(key, call.value)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = load(ctx)
+}
+
+// add insere ou atualiza a chave e remove as entradas menos usadas além do limite
+func (c *lruCache[V]) add(key string, value V) {
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.items[key]; ok {
+		el.Value = &cacheEntry[V]{key: key, value: value, expires: expires}
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&cacheEntry[V]{key: key, value: value, expires: expires})
+	for c.size > 0 && c.ll.Len() > c.size {
+		c.removeElement(c.ll.Back())
+		c.evictions.Add(1)
+	}
+}
+
+func (c *lruCache[V]) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.items, el.Value.(*cacheEntry[V]).key)
+}
+
+// Stats retorna uma cópia dos contadores atuais
+func (c *lruCache[V]) Stats() cacheStats {
+	c.mu.Lock()
+	entries := c.ll.Len()
+	c.mu.Unlock()
+
+	return cacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Coalesced: c.coalesced.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// cachedProvider envolve outro WeatherProvider, guardando as respostas em cache
+// para economizar a cota da API nas cidades mais consultadas.
+type cachedProvider struct {
+	WeatherProvider
+
+	current  *lruCache[*Weather]
+	forecast *lruCache[*Forecast]
+}
+
+func newCachedProvider(provider WeatherProvider, ttl time.Duration, size int) *cachedProvider {
+	return &cachedProvider{
+		WeatherProvider: provider,
+		current:         newLRUCache[*Weather](ttl, size),
+		forecast:        newLRUCache[*Forecast](ttl, size),
+	}
+}
+
+// CurrentWeather consulta o cache antes de chamar o fornecedor
+func (p *cachedProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	// A entrada é compartilhada entre grafias diferentes da mesma cidade ("São Paulo", "sao paulo"),
+	// que recebem o mesmo nome canônico resolvido pelo fornecedor.
+	loaded := false
+	weather, err := p.current.Get(ctx, q.key(), func(ctx context.Context) (*Weather, error) {
+		loaded = true
+		return p.WeatherProvider.CurrentWeather(ctx, q)
+	})
+	if err != nil || loaded {
+		return weather, err
+	}
+
+	// Resposta reaproveitada (do cache ou de uma carga simultânea): a cópia devolvida é marcada
+	w := *weather
+	w.Cached = true
+	return &w, nil
+}
+
+// Forecast consulta o cache antes de chamar o fornecedor
+func (p *cachedProvider) Forecast(ctx context.Context, q WeatherQuery, days int) (*Forecast, error) {
+	key := q.key() + "|" + strconv.Itoa(days)
+	return p.forecast.Get(ctx, key, func(ctx context.Context) (*Forecast, error) {
+		return p.WeatherProvider.Forecast(ctx, q, days)
+	})
+}
+
+// Stats retorna os contadores dos caches de clima atual e previsão
+func (p *cachedProvider) Stats() map[string]cacheStats {
+	return map[string]cacheStats{
+		"current":  p.current.Stats(),
+		"forecast": p.forecast.Stats(),
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheCoalescesConcurrentLoads(t *testing.T) {
+	c := newLRUCache[int](time.Minute, 10)
+	release := make(chan struct{})
+	var loads atomic.Int32
+	load := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	// A primeira chamada inicia a carga; as demais devem aguardar a mesma carga
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]int, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = c.Get(context.Background(), "london", load)
+		}(i)
+	}
+	for c.Stats().Misses+c.Stats().Coalesced < callers {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("cargas = %d, quer 1", n)
+	}
+	for i := range results {
+		if results[i] != 42 || errs[i] != nil {
+			t.Errorf("chamada %d = %d, %v, quer 42", i, results[i], errs[i])
+		}
+	}
+	if st := c.Stats(); st.Misses != 1 || st.Coalesced != callers-1 || st.Entries != 1 {
+		t.Errorf("stats = %+v, quer 1 falta, %d agrupadas e 1 entrada", st, callers-1)
+	}
+}
+
+func TestLRUCacheWaiterCancelKeepsLoad(t *testing.T) {
+	c := newLRUCache[int](time.Minute, 10)
+	release := make(chan struct{})
+	load := func(ctx context.Context) (int, error) {
+		select {
+		case <-release:
+			return 7, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	// Quem iniciou a carga desiste; a carga continua e atende quem ainda aguarda
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := c.Get(ctx, "london", load)
+		first <- err
+	}()
+	for c.Stats().Misses == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Fatalf("primeira chamada = %v, quer context.Canceled", err)
+	}
+
+	second := make(chan int, 1)
+	go func() {
+		v, _ := c.Get(context.Background(), "london", load)
+		second <- v
+	}()
+	for c.Stats().Coalesced == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if v := <-second; v != 7 {
+		t.Errorf("segunda chamada = %d, quer 7 da carga iniciada pela primeira", v)
+	}
+}
+
+func TestLRUCacheLoaderPanic(t *testing.T) {
+	c := newLRUCache[int](time.Minute, 10)
+	_, err := c.Get(context.Background(), "london", func(ctx context.Context) (int, error) {
+		panic("falha inesperada")
+	})
+	if err == nil {
+		t.Fatalf("Get depois de um pânico na carga retornou sem erro")
+	}
+
+	// O pânico não fica em cache nem deixa a chave presa: a próxima chamada carrega de novo
+	v, err := c.Get(context.Background(), "london", func(ctx context.Context) (int, error) {
+		return 3, nil
+	})
+	if v != 3 || err != nil {
+		t.Errorf("Get depois do pânico = %d, %v, quer 3", v, err)
+	}
+}
+
+func TestLRUCacheErrorsAreNotCached(t *testing.T) {
+	c := newLRUCache[int](time.Minute, 10)
+	boom := errors.New("fornecedor indisponível")
+	if _, err := c.Get(context.Background(), "london", func(ctx context.Context) (int, error) { return 0, boom }); !errors.Is(err, boom) {
+		t.Fatalf("Get = %v, quer o erro da carga", err)
+	}
+	if st := c.Stats(); st.Entries != 0 {
+		t.Errorf("entradas = %d depois de um erro, quer 0", st.Entries)
+	}
+}
+
+func TestLRUCacheEvictionAndExpiry(t *testing.T) {
+	now := time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+	c := newLRUCache[string](time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	loads := 0
+	get := func(key string) string {
+		v, _ := c.Get(context.Background(), key, func(ctx context.Context) (string, error) {
+			loads++
+			return key, nil
+		})
+		return v
+	}
+
+	// "a" é usada depois de "b", então "b" é a menos usada quando "c" entra
+	get("a")
+	get("b")
+	get("a")
+	get("c")
+	if st := c.Stats(); st.Evictions != 1 || st.Entries != 2 {
+		t.Fatalf("stats = %+v, quer 1 remoção e 2 entradas", st)
+	}
+	tests := []struct {
+		key       string
+		wantLoads int // total de cargas depois da consulta
+	}{
+		{"a", 3}, // ainda em cache
+		{"c", 3}, // ainda em cache
+		{"b", 4}, // removida: carrega de novo e remove "a"
+		{"a", 5},
+	}
+	for _, tt := range tests {
+		if get(tt.key) != tt.key || loads != tt.wantLoads {
+			t.Errorf("depois de %q: %d cargas, quer %d", tt.key, loads, tt.wantLoads)
+		}
+	}
+
+	// Entradas vencidas são carregadas de novo
+	now = now.Add(time.Minute)
+	get("a")
+	if loads != 6 {
+		t.Errorf("cargas = %d depois do TTL, quer 6", loads)
+	}
+}
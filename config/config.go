@@ -0,0 +1,397 @@
+// Package config carrega a configuração do servidor gRPC e do gateway HTTP.
+//
+// Os valores são resolvidos na seguinte ordem de precedência (o último vence):
+//
+//  1. valores padrão definidos neste pacote;
+//  2. arquivo JSON opcional, indicado por -config ou pela variável WEATHER_CONFIG,
+//     com as seções "grpc" e "gateway";
+//  3. variáveis de ambiente, derivadas do nome da flag com o prefixo da seção
+//     (ex.: -cache-ttl do servidor gRPC -> WEATHER_GRPC_CACHE_TTL);
+//  4. flags de linha de comando.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GRPCConfig reúne as opções do servidor gRPC (server_grpc.go)
+type GRPCConfig struct {
+	// Endereço em que o servidor gRPC escuta
+	Addr string `json:"addr"`
+	// Endereço HTTP dos endpoints administrativos (vazio desabilita)
+	AdminAddr string `json:"adminAddr"`
+	// Fornecedores de clima em ordem de preferência, separados por vírgula
+	// (ex.: "openweather,fixture"): openweather ou fixture
+	Provider string `json:"provider"`
+	// Diretório com as respostas JSON usadas pelo fornecedor fixture
+	FixturesDir string `json:"fixturesDir"`
+	// Arquivo com a chave da API do OpenWeather (recarregado ao mudar).
+	// Sem ele, a chave é lida da variável WEATHER_OPENWEATHER_API_KEY.
+	OpenWeatherKeyFile string `json:"openWeatherKeyFile"`
+	// URL base da API do OpenWeather
+	OpenWeatherURL string `json:"openWeatherURL"`
+	// Chamadas permitidas por minuto com a chave do OpenWeather (0 não limita)
+	OpenWeatherPerMinute int `json:"openWeatherPerMinute"`
+	// Chamadas permitidas por dia com a chave do OpenWeather (0 não limita)
+	OpenWeatherPerDay int `json:"openWeatherPerDay"`
+	// Espera máxima na fila por cota antes de recusar a chamada com ResourceExhausted
+	QuotaWait Duration `json:"quotaWait"`
+	// Fonte da busca de cidades: offline (índice embutido) ou openweather
+	Geocoder string `json:"geocoder"`
+	// URL base da API de geocodificação do OpenWeather
+	OpenWeatherGeoURL string `json:"openWeatherGeoURL"`
+	// Tempo de vida das respostas em cache (0 desabilita o cache)
+	CacheTTL Duration `json:"cacheTTL"`
+	// Quantidade máxima de entradas em cache
+	CacheSize int `json:"cacheSize"`
+	// Tempo máximo de cada tentativa de chamada HTTP a um fornecedor externo.
+	// Ajuste junto com UpstreamRetries, UpstreamBackoff e QuotaWait: o tempo total de uma
+	// chamada (ver UpstreamBudget) precisa caber no Timeout do gateway.
+	UpstreamTimeout Duration `json:"upstreamTimeout"`
+	// Novas tentativas após falhas transitórias (rede, timeout, HTTP 5xx) de um fornecedor externo
+	UpstreamRetries int `json:"upstreamRetries"`
+	// Espera antes da primeira nova tentativa, dobrada a cada tentativa seguinte
+	UpstreamBackoff Duration `json:"upstreamBackoff"`
+	// Falhas seguidas que abrem o circuit breaker das chamadas HTTP a um fornecedor
+	BreakerThreshold int `json:"breakerThreshold"`
+	// Tempo com o circuito aberto antes de uma nova chamada de teste ao fornecedor
+	BreakerCooldown Duration `json:"breakerCooldown"`
+	// Intervalo de consulta das cidades com assinaturas ativas
+	PollInterval Duration `json:"pollInterval"`
+	// Consultas simultâneas ao fornecedor em GetWeatherBatch
+	BatchWorkers int `json:"batchWorkers"`
+	// Armazenamento das observações: memory ou file
+	Store string `json:"store"`
+	// Arquivo das observações com o armazenamento file
+	StorePath string `json:"storePath"`
+	// Quantidade máxima de observações guardadas por cidade no histórico (GetHistory)
+	HistorySize int `json:"historySize"`
+	// Tempo máximo de retenção das observações (0 guarda sem limite de idade)
+	HistoryRetention Duration `json:"historyRetention"`
+	// Intervalo da compactação, que descarta as observações fora da retenção
+	CompactInterval Duration `json:"compactInterval"`
+	// Idade máxima da última observação servida quando o fornecedor falha (0 desabilita)
+	MaxStaleness Duration `json:"maxStaleness"`
+
+	RateLimitConfig
+	AuthConfig
+}
+
+// GatewayConfig reúne as opções do gateway HTTP (server/server.go)
+type GatewayConfig struct {
+	// Endereço em que o gateway HTTP escuta
+	Addr string `json:"addr"`
+	// Endereço do servidor gRPC
+	GRPCTarget string `json:"grpcTarget"`
+	// Tempo máximo de uma chamada gRPC simples. Deve ser maior que o tempo total das chamadas
+	// do servidor gRPC aos fornecedores (GRPCConfig.UpstreamBudget), senão as novas tentativas
+	// não chegam a acontecer.
+	Timeout Duration `json:"timeout"`
+	// Tempo máximo de uma chamada gRPC em lote
+	BatchTimeout Duration `json:"batchTimeout"`
+	// Diretório com os arquivos do frontend (index.html, .wasm, .js)
+	StaticDir string `json:"staticDir"`
+
+	RateLimitConfig
+	AuthConfig
+}
+
+// RateLimitConfig reúne as opções do limite de requisições por cliente,
+// comuns ao servidor gRPC e ao gateway HTTP (pacote ratelimit)
+type RateLimitConfig struct {
+	// Requisições por janela de cada IP sem chave de API aceita (0 não limita)
+	RateLimitPerIP int `json:"rateLimitPerIP"`
+	// Requisições por janela de cada chave de API (0 não limita)
+	RateLimitPerKey int `json:"rateLimitPerKey"`
+	// Duração da janela de contagem
+	RateLimitWindow Duration `json:"rateLimitWindow"`
+	// Arquivo com as chaves de API aceitas, uma por linha, seguidas dos escopos
+	// opcionais usados pela autenticação (vazio não aceita chaves)
+	APIKeysFile string `json:"apiKeysFile"`
+	// Proxies (IPs ou CIDRs separados por vírgula) cujo X-Forwarded-For identifica o cliente
+	TrustedProxies string `json:"trustedProxies"`
+	// Origens (IPs ou CIDRs separados por vírgula) que nunca são limitadas. No servidor gRPC,
+	// o gateway HTTP, que já limita os seus clientes: assim cada cliente é contado uma única vez.
+	RateLimitExempt string `json:"rateLimitExempt"`
+}
+
+// AuthConfig reúne as opções da autenticação por token, comuns ao servidor gRPC e ao
+// gateway HTTP (pacote auth). As chaves de API estáticas vêm de APIKeysFile.
+type AuthConfig struct {
+	// Exige token de acesso (chave de API ou JWT) nas rotas da API
+	AuthRequired bool `json:"authRequired"`
+	// Arquivo JWKS com as chaves públicas que assinam os JWTs (vazio não aceita JWTs)
+	JWKSFile string `json:"jwksFile"`
+	// Emissor (iss) exigido nos JWTs (vazio não verifica)
+	JWTIssuer string `json:"jwtIssuer"`
+	// Audiência (aud) exigida nos JWTs (vazio não verifica)
+	JWTAudience string `json:"jwtAudience"`
+}
+
+// DefaultGRPC retorna a configuração padrão do servidor gRPC
+func DefaultGRPC() GRPCConfig {
+	return GRPCConfig{
+		Addr:                 ":50051",
+		AdminAddr:            "localhost:50052",
+		Provider:             "openweather",
+		FixturesDir:          "fixtures",
+		OpenWeatherURL:       "http://api.openweathermap.org/data/2.5",
+		OpenWeatherPerMinute: 60,
+		QuotaWait:            Duration(2 * time.Second),
+		Geocoder:             "offline",
+		OpenWeatherGeoURL:    "http://api.openweathermap.org/geo/1.0",
+		CacheTTL:             Duration(5 * time.Minute),
+		CacheSize:            1000,
+		UpstreamTimeout:      Duration(2 * time.Second),
+		UpstreamRetries:      2,
+		UpstreamBackoff:      Duration(200 * time.Millisecond),
+		BreakerThreshold:     5,
+		BreakerCooldown:      Duration(30 * time.Second),
+		PollInterval:         Duration(time.Minute),
+		BatchWorkers:         8,
+		Store:                "file",
+		StorePath:            "var/observations.jsonl",
+		HistorySize:          10000,
+		HistoryRetention:     Duration(30 * 24 * time.Hour),
+		CompactInterval:      Duration(time.Hour),
+		MaxStaleness:         Duration(6 * time.Hour),
+		// O gateway na mesma máquina já limita os seus clientes e fica isento aqui
+		RateLimitConfig: defaultRateLimit("127.0.0.1/8,::1"),
+	}
+}
+
+// DefaultGateway retorna a configuração padrão do gateway HTTP
+func DefaultGateway() GatewayConfig {
+	return GatewayConfig{
+		Addr:         ":8080",
+		GRPCTarget:   "localhost:50051",
+		Timeout:      Duration(10 * time.Second),
+		BatchTimeout: Duration(15 * time.Second),
+		StaticDir:    "frontend",
+
+		RateLimitConfig: defaultRateLimit(""),
+	}
+}
+
+func (c *GRPCConfig) bind(fs *flag.FlagSet) {
+	fs.StringVar(&c.Addr, "addr", c.Addr, "endereço em que o servidor gRPC escuta")
+	fs.StringVar(&c.AdminAddr, "admin-addr", c.AdminAddr, "endereço HTTP dos endpoints administrativos (vazio desabilita)")
+	fs.StringVar(&c.Provider, "provider", c.Provider, "fornecedores de clima em ordem de preferência, separados por vírgula: openweather, fixture")
+	fs.StringVar(&c.FixturesDir, "fixtures", c.FixturesDir, "diretório com as respostas JSON usadas pelo fornecedor fixture")
+	fs.StringVar(&c.OpenWeatherKeyFile, "openweather-key-file", c.OpenWeatherKeyFile, "arquivo com a chave da API do OpenWeather")
+	fs.StringVar(&c.OpenWeatherURL, "openweather-url", c.OpenWeatherURL, "URL base da API do OpenWeather")
+	fs.StringVar(&c.Geocoder, "geocoder", c.Geocoder, "fonte da busca de cidades: offline ou openweather")
+	fs.StringVar(&c.OpenWeatherGeoURL, "openweather-geo-url", c.OpenWeatherGeoURL, "URL base da API de geocodificação do OpenWeather")
+	fs.Var(&c.CacheTTL, "cache-ttl", "tempo de vida das respostas em cache (0 desabilita o cache)")
+	fs.IntVar(&c.CacheSize, "cache-size", c.CacheSize, "quantidade máxima de entradas em cache")
+	fs.IntVar(&c.OpenWeatherPerMinute, "openweather-per-minute", c.OpenWeatherPerMinute, "chamadas por minuto permitidas com a chave do OpenWeather (0 não limita)")
+	fs.IntVar(&c.OpenWeatherPerDay, "openweather-per-day", c.OpenWeatherPerDay, "chamadas por dia permitidas com a chave do OpenWeather (0 não limita)")
+	fs.Var(&c.QuotaWait, "quota-wait", "espera máxima na fila por cota antes de recusar a chamada")
+	fs.Var(&c.UpstreamTimeout, "upstream-timeout", "tempo máximo de cada tentativa de chamada HTTP a um fornecedor externo")
+	fs.IntVar(&c.UpstreamRetries, "upstream-retries", c.UpstreamRetries, "novas tentativas após falhas transitórias de um fornecedor externo (0 desabilita)")
+	fs.Var(&c.UpstreamBackoff, "upstream-backoff", "espera antes da primeira nova tentativa, dobrada a cada tentativa")
+	fs.IntVar(&c.BreakerThreshold, "breaker-threshold", c.BreakerThreshold, "falhas seguidas que abrem o circuit breaker de um fornecedor")
+	fs.Var(&c.BreakerCooldown, "breaker-cooldown", "tempo com o circuito aberto antes de testar o fornecedor novamente")
+	fs.Var(&c.PollInterval, "poll-interval", "intervalo de consulta das cidades com assinaturas ativas")
+	fs.IntVar(&c.BatchWorkers, "batch-workers", c.BatchWorkers, "consultas simultâneas ao fornecedor em GetWeatherBatch")
+	fs.StringVar(&c.Store, "store", c.Store, "armazenamento das observações: memory ou file")
+	fs.StringVar(&c.StorePath, "store-path", c.StorePath, "arquivo das observações com o armazenamento file")
+	fs.IntVar(&c.HistorySize, "history-size", c.HistorySize, "quantidade máxima de observações guardadas por cidade no histórico")
+	fs.Var(&c.HistoryRetention, "history-retention", "tempo máximo de retenção das observações (0 guarda sem limite de idade)")
+	fs.Var(&c.CompactInterval, "compact-interval", "intervalo da compactação das observações")
+	fs.Var(&c.MaxStaleness, "max-staleness", "idade máxima da última observação servida quando o fornecedor falha (0 desabilita)")
+	c.RateLimitConfig.bind(fs)
+	c.AuthConfig.bind(fs)
+}
+
+// Validate verifica se a configuração do servidor gRPC é utilizável
+func (c *GRPCConfig) Validate() error {
+	if c.Addr == "" {
+		return fmt.Errorf("addr não pode ser vazio")
+	}
+	seen := make(map[string]bool)
+	for _, provider := range c.Providers() {
+		switch provider {
+		case "openweather":
+			if c.OpenWeatherURL == "" {
+				return fmt.Errorf("openweather-url não pode ser vazio com o fornecedor openweather")
+			}
+		case "fixture":
+			if c.FixturesDir == "" {
+				return fmt.Errorf("fixtures não pode ser vazio com o fornecedor fixture")
+			}
+		default:
+			return fmt.Errorf("fornecedor desconhecido: %q (use openweather ou fixture)", provider)
+		}
+		if seen[provider] {
+			return fmt.Errorf("fornecedor repetido em provider: %q", provider)
+		}
+		seen[provider] = true
+	}
+	switch c.Geocoder {
+	case "offline":
+	case "openweather":
+		if c.OpenWeatherGeoURL == "" {
+			return fmt.Errorf("openweather-geo-url não pode ser vazio com a busca de cidades openweather")
+		}
+	default:
+		return fmt.Errorf("fonte de busca de cidades desconhecida: %q (use offline ou openweather)", c.Geocoder)
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("cache-ttl não pode ser negativo")
+	}
+	if c.CacheSize < 1 {
+		return fmt.Errorf("cache-size deve ser maior que zero")
+	}
+	if c.OpenWeatherPerMinute < 0 || c.OpenWeatherPerDay < 0 {
+		return fmt.Errorf("os limites de chamadas do OpenWeather não podem ser negativos")
+	}
+	if c.QuotaWait < 0 {
+		return fmt.Errorf("quota-wait não pode ser negativo")
+	}
+	if c.UpstreamTimeout <= 0 {
+		return fmt.Errorf("upstream-timeout deve ser maior que zero")
+	}
+	if c.UpstreamRetries < 0 {
+		return fmt.Errorf("upstream-retries não pode ser negativo")
+	}
+	if c.UpstreamBackoff <= 0 {
+		return fmt.Errorf("upstream-backoff deve ser maior que zero")
+	}
+	if c.BreakerThreshold < 1 {
+		return fmt.Errorf("breaker-threshold deve ser maior que zero")
+	}
+	if c.BreakerCooldown <= 0 {
+		return fmt.Errorf("breaker-cooldown deve ser maior que zero")
+	}
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("poll-interval deve ser maior que zero")
+	}
+	if c.BatchWorkers < 1 {
+		return fmt.Errorf("batch-workers deve ser maior que zero")
+	}
+	switch c.Store {
+	case "memory":
+	case "file":
+		if c.StorePath == "" {
+			return fmt.Errorf("store-path não pode ser vazio com o armazenamento file")
+		}
+	default:
+		return fmt.Errorf("armazenamento desconhecido: %q (use memory ou file)", c.Store)
+	}
+	if c.HistorySize < 1 {
+		return fmt.Errorf("history-size deve ser maior que zero")
+	}
+	if c.HistoryRetention < 0 {
+		return fmt.Errorf("history-retention não pode ser negativo")
+	}
+	if c.CompactInterval <= 0 {
+		return fmt.Errorf("compact-interval deve ser maior que zero")
+	}
+	if c.MaxStaleness < 0 {
+		return fmt.Errorf("max-staleness não pode ser negativo")
+	}
+	if err := c.RateLimitConfig.validate(); err != nil {
+		return err
+	}
+	return c.AuthConfig.validate(c.APIKeysFile)
+}
+
+// UpstreamBudget retorna o tempo máximo de uma chamada a um fornecedor externo: a espera
+// por cota, todas as tentativas com o seu timeout e as esperas entre elas (sem o jitter, que
+// só as encurta). Com os valores padrão, 2s + 3 × 2s + 200ms + 400ms = 8,6s, dentro do
+// timeout padrão do gateway (10s).
+func (c *GRPCConfig) UpstreamBudget() time.Duration {
+	budget := c.QuotaWait.Std() + time.Duration(c.UpstreamRetries+1)*c.UpstreamTimeout.Std()
+	for i := 0; i < c.UpstreamRetries; i++ {
+		budget += c.UpstreamBackoff.Std() << uint(i)
+	}
+	return budget
+}
+
+// Providers retorna os nomes dos fornecedores de clima em ordem de preferência
+func (c *GRPCConfig) Providers() []string {
+	var names []string
+	for _, name := range strings.Split(c.Provider, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (c *GatewayConfig) bind(fs *flag.FlagSet) {
+	fs.StringVar(&c.Addr, "addr", c.Addr, "endereço em que o gateway HTTP escuta")
+	fs.StringVar(&c.GRPCTarget, "grpc-target", c.GRPCTarget, "endereço do servidor gRPC")
+	fs.Var(&c.Timeout, "timeout", "tempo máximo de uma chamada gRPC simples")
+	fs.Var(&c.BatchTimeout, "batch-timeout", "tempo máximo de uma chamada gRPC em lote")
+	fs.StringVar(&c.StaticDir, "static-dir", c.StaticDir, "diretório com os arquivos do frontend")
+	c.RateLimitConfig.bind(fs)
+	c.AuthConfig.bind(fs)
+}
+
+// Validate verifica se a configuração do gateway é utilizável
+func (c *GatewayConfig) Validate() error {
+	if c.Addr == "" {
+		return fmt.Errorf("addr não pode ser vazio")
+	}
+	if c.GRPCTarget == "" {
+		return fmt.Errorf("grpc-target não pode ser vazio")
+	}
+	if c.Timeout <= 0 || c.BatchTimeout <= 0 {
+		return fmt.Errorf("timeout e batch-timeout devem ser maiores que zero")
+	}
+	if c.StaticDir == "" {
+		return fmt.Errorf("static-dir não pode ser vazio")
+	}
+	if err := c.RateLimitConfig.validate(); err != nil {
+		return err
+	}
+	return c.AuthConfig.validate(c.APIKeysFile)
+}
+
+func defaultRateLimit(exempt string) RateLimitConfig {
+	return RateLimitConfig{
+		RateLimitPerIP:  120,
+		RateLimitPerKey: 1200,
+		RateLimitWindow: Duration(time.Minute),
+		RateLimitExempt: exempt,
+	}
+}
+
+func (c *RateLimitConfig) bind(fs *flag.FlagSet) {
+	fs.IntVar(&c.RateLimitPerIP, "rate-limit-per-ip", c.RateLimitPerIP, "requisições por janela de cada IP sem chave de API (0 não limita)")
+	fs.IntVar(&c.RateLimitPerKey, "rate-limit-per-key", c.RateLimitPerKey, "requisições por janela de cada chave de API (0 não limita)")
+	fs.Var(&c.RateLimitWindow, "rate-limit-window", "duração da janela de contagem das requisições")
+	fs.StringVar(&c.APIKeysFile, "api-keys-file", c.APIKeysFile, "arquivo com as chaves de API aceitas, uma por linha")
+	fs.StringVar(&c.TrustedProxies, "trusted-proxies", c.TrustedProxies, "proxies (IPs ou CIDRs separados por vírgula) cujo X-Forwarded-For identifica o cliente")
+	fs.StringVar(&c.RateLimitExempt, "rate-limit-exempt", c.RateLimitExempt, "origens (IPs ou CIDRs separados por vírgula) que nunca são limitadas, como o gateway HTTP no servidor gRPC")
+}
+
+func (c *RateLimitConfig) validate() error {
+	if c.RateLimitPerIP < 0 || c.RateLimitPerKey < 0 {
+		return fmt.Errorf("rate-limit-per-ip e rate-limit-per-key não podem ser negativos")
+	}
+	if c.RateLimitWindow <= 0 {
+		return fmt.Errorf("rate-limit-window deve ser maior que zero")
+	}
+	return nil
+}
+
+func (c *AuthConfig) bind(fs *flag.FlagSet) {
+	fs.BoolVar(&c.AuthRequired, "auth-required", c.AuthRequired, "exige token de acesso (chave de API ou JWT) nas rotas da API")
+	fs.StringVar(&c.JWKSFile, "jwks-file", c.JWKSFile, "arquivo JWKS com as chaves públicas que assinam os JWTs")
+	fs.StringVar(&c.JWTIssuer, "jwt-issuer", c.JWTIssuer, "emissor (iss) exigido nos JWTs (vazio não verifica)")
+	fs.StringVar(&c.JWTAudience, "jwt-audience", c.JWTAudience, "audiência (aud) exigida nos JWTs (vazio não verifica)")
+}
+
+// validate recebe o arquivo de chaves de API, que fica em RateLimitConfig
+func (c *AuthConfig) validate(apiKeysFile string) error {
+	if c.AuthRequired && apiKeysFile == "" && c.JWKSFile == "" {
+		return fmt.Errorf("auth-required exige api-keys-file ou jwks-file")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package config
+
+import "testing"
+
+// Os padrões do servidor gRPC e do gateway precisam ser coerentes entre si: com o timeout
+// do gateway menor que o tempo das chamadas aos fornecedores, as novas tentativas nunca rodam.
+func TestDefaultUpstreamBudgetFitsGatewayTimeout(t *testing.T) {
+	grpc, gateway := DefaultGRPC(), DefaultGateway()
+	if budget := grpc.UpstreamBudget(); budget >= gateway.Timeout.Std() {
+		t.Errorf("UpstreamBudget = %s, quer menos que o timeout do gateway (%s)", budget, gateway.Timeout.Std())
+	}
+}
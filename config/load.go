@@ -0,0 +1,160 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+// Variável de ambiente com o caminho do arquivo de configuração
+const configEnv = "WEATHER_CONFIG"
+
+// section é implementada por cada bloco de configuração carregável
+type section interface {
+	bind(fs *flag.FlagSet)
+	Validate() error
+}
+
+// LoadGRPC carrega a configuração do servidor gRPC a partir dos argumentos de linha de comando
+func LoadGRPC(args []string) (*GRPCConfig, error) {
+	cfg := DefaultGRPC()
+	if err := load(args, "grpc", "WEATHER_GRPC_", &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// LoadGateway carrega a configuração do gateway HTTP a partir dos argumentos de linha de comando
+func LoadGateway(args []string) (*GatewayConfig, error) {
+	cfg := DefaultGateway()
+	if err := load(args, "gateway", "WEATHER_GATEWAY_", &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// load aplica arquivo, ambiente e flags sobre os valores padrão já presentes em cfg
+func load(args []string, name, envPrefix string, cfg section) error {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(configEnv), "arquivo JSON de configuração (opcional)")
+
+	// O caminho do arquivo precisa ser conhecido antes das demais flags,
+	// pois o arquivo tem precedência menor que elas.
+	if path := findConfigFlag(args, *configPath); path != "" {
+		if err := loadFile(path, name, cfg); err != nil {
+			return err
+		}
+	}
+
+	cfg.bind(fs)
+
+	// As variáveis de ambiente reaproveitam a conversão de tipos das próprias flags
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" || envErr != nil {
+			return
+		}
+		env := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if v, ok := os.LookupEnv(env); ok {
+			if err := fs.Set(f.Name, v); err != nil {
+				envErr = fmt.Errorf("valor inválido em %s: %v", env, err)
+			}
+		}
+	})
+	if envErr != nil {
+		return envErr
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuração inválida: %v", err)
+	}
+	return nil
+}
+
+// loadFile lê a seção correspondente do arquivo JSON de configuração
+func loadFile(path, name string, cfg section) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("falha ao ler arquivo de configuração: %v", err)
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return fmt.Errorf("arquivo de configuração inválido: %v", err)
+	}
+
+	raw, ok := sections[name]
+	if !ok {
+		return nil
+	}
+	dec := json.NewDecoder(strings.NewReader(string(raw)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return fmt.Errorf("seção %q do arquivo de configuração inválida: %v", name, err)
+	}
+	return nil
+}
+
+// findConfigFlag procura -config nos argumentos, aceitando as formas
+// "-config arq", "-config=arq" e as variantes com "--". Retorna def se não houver.
+func findConfigFlag(args []string, def string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if name == "config" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(name, "config=") {
+			return strings.TrimPrefix(name, "config=")
+		}
+	}
+	return def
+}
+
+// Duration é um time.Duration que aceita texto como "5m" ou "1s" no JSON e nas flags
+type Duration time.Duration
+
+// Std converte para time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// Set implementa flag.Value
+func (d *Duration) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duração deve ser um texto como \"5m\": %v", err)
+	}
+	return d.Set(s)
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domínio informado nos detalhes de erro (errdetails.ErrorInfo)
+const errorDomain = "weather.grpc-client"
+
+// Categorias de falha reconhecidas pelo servidor. Os fornecedores embrulham
+// estes erros (com %w) para que o servidor escolha o código gRPC adequado.
+var (
+	// A cidade (ou outro recurso pedido) não existe no fornecedor
+	errCityNotFound = errors.New("cidade não encontrada")
+	// O fornecedor está fora do ar, inacessível ou respondeu com erro interno
+	errUpstreamUnavailable = errors.New("fornecedor de clima indisponível")
+	// O fornecedor recusou a chamada por limite de uso
+	errUpstreamRateLimited = errors.New("limite de chamadas ao fornecedor de clima atingido")
+	// O fornecedor recusou a chave de API (configuração do servidor)
+	errUpstreamUnauthorized = errors.New("chave de API recusada pelo fornecedor de clima")
+	// O fornecedor respondeu algo que não conseguimos interpretar (JSON inválido, campos ausentes)
+	errUpstreamBadResponse = errors.New("resposta inválida do fornecedor de clima")
+)
+
+// upstreamStatusError descreve uma resposta de erro do fornecedor (status HTTP,
+// código e mensagem do corpo) e a categoria à qual ela corresponde.
+type upstreamStatusError struct {
+	kind       error
+	StatusCode int
+	Message    string
+}
+
+func (e *upstreamStatusError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%v (HTTP %d)", e.kind, e.StatusCode)
+	}
+	return fmt.Sprintf("%v (HTTP %d: %s)", e.kind, e.StatusCode, e.Message)
+}
+
+func (e *upstreamStatusError) Unwrap() error {
+	return e.kind
+}
+
+// invalidArgumentError indica um campo inválido na requisição do cliente
+type invalidArgumentError struct {
+	Field       string
+	Description string
+}
+
+func (e *invalidArgumentError) Error() string {
+	return fmt.Sprintf("%s inválido: %s", e.Field, e.Description)
+}
+
+// retryAfterError acompanha um erro do fornecedor com o tempo sugerido para nova tentativa
+type retryAfterError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAfterError) Unwrap() error {
+	return e.err
+}
+
+// withRetryAfter associa ao erro o tempo de espera sugerido pelo fornecedor
+func withRetryAfter(err error, d time.Duration) error {
+	if d <= 0 {
+		return err
+	}
+	return &retryAfterError{err: err, retryAfter: d}
+}
+
+// toStatus converte um erro interno em um status gRPC com detalhes estruturados.
+// Erros que já são status gRPC são devolvidos sem alteração.
+func toStatus(err error, city string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var (
+		code    codes.Code
+		reason  string
+		details []protoadapt.MessageV1
+	)
+
+	var invalid *invalidArgumentError
+	switch {
+	case errors.As(err, &invalid):
+		code, reason = codes.InvalidArgument, "INVALID_ARGUMENT"
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: invalid.Field, Description: invalid.Description},
+			},
+		})
+	case errors.Is(err, errCityNotFound):
+		code, reason = codes.NotFound, "CITY_NOT_FOUND"
+		details = append(details, &errdetails.ResourceInfo{
+			ResourceType: "city",
+			ResourceName: city,
+			Description:  err.Error(),
+		})
+	case errors.Is(err, errUpstreamRateLimited):
+		code, reason = codes.ResourceExhausted, "UPSTREAM_RATE_LIMITED"
+		details = append(details, &errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{
+				{Subject: "upstream", Description: err.Error()},
+			},
+		})
+	case errors.Is(err, errUpstreamUnavailable):
+		code, reason = codes.Unavailable, "UPSTREAM_UNAVAILABLE"
+	case errors.Is(err, errUpstreamUnauthorized):
+		code, reason = codes.Internal, "UPSTREAM_UNAUTHORIZED"
+	case errors.Is(err, errUpstreamBadResponse):
+		code, reason = codes.Internal, "UPSTREAM_BAD_RESPONSE"
+	case errors.Is(err, context.DeadlineExceeded):
+		code, reason = codes.DeadlineExceeded, "DEADLINE_EXCEEDED"
+	case errors.Is(err, context.Canceled):
+		code, reason = codes.Canceled, "CANCELED"
+	default:
+		code, reason = codes.Internal, "INTERNAL"
+	}
+
+	var retry *retryAfterError
+	if errors.As(err, &retry) {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(retry.retryAfter)})
+	}
+
+	info := &errdetails.ErrorInfo{Reason: reason, Domain: errorDomain}
+	if city != "" {
+		info.Metadata = map[string]string{"city": city}
+	}
+
+	st := status.New(code, err.Error())
+	withDetails, detailErr := st.WithDetails(append([]protoadapt.MessageV1{info}, details...)...)
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limites do horizonte de previsão em dias (o plano gratuito do OpenWeather cobre 5 dias)
+const (
+	defaultForecastDays = 3
+	maxForecastDays     = 5
+)
+
+// ForecastEntry representa um intervalo de previsão (horário ou diário)
+type ForecastEntry struct {
+	Time                     time.Time
+	Temperature              float32
+	TempMin                  float32
+	TempMax                  float32
+	PrecipitationProbability float32
+	WindSpeed                float32
+	Humidity                 int32
+	ConditionCode            int32
+	Description              string
+}
+
+// Forecast agrupa as previsões horárias e o resumo diário de uma cidade,
+// identificada pelos mesmos campos de Weather
+type Forecast struct {
+	City        string
+	Country     string
+	CityID      int64
+	Coordinates Coordinates
+	Units       Units
+	Hourly      []ForecastEntry
+	Daily       []ForecastEntry
+	// Fornecedor que produziu a previsão
+	Source string
+}
+
+// forecastDays valida o horizonte pedido pelo cliente, aplicando o padrão quando zero
+func forecastDays(days int32) (int, error) {
+	if days == 0 {
+		return defaultForecastDays, nil
+	}
+	if days < 1 || days > maxForecastDays {
+		return 0, &invalidArgumentError{
+			Field:       "days",
+			Description: fmt.Sprintf("horizonte de %d dias fora do intervalo de 1 a %d", days, maxForecastDays),
+		}
+	}
+	return int(days), nil
+}
+
+// dailyFromHourly resume as entradas horárias em um registro por dia local.
+// O deslocamento de fuso (em segundos) define onde cada dia começa. Mínima e máxima
+// são os extremos do dia, a probabilidade de chuva é a maior do dia, vento e umidade
+// são médias, e a condição vem do intervalo mais próximo do meio-dia.
+func dailyFromHourly(hourly []ForecastEntry, tzOffset int) []ForecastEntry {
+	loc := time.FixedZone("", tzOffset)
+
+	var daily []ForecastEntry
+	var count int
+	var tempSum, windSum float32
+	var humiditySum int32
+	var middayDistance int
+
+	for _, h := range hourly {
+		local := h.Time.In(loc)
+		dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+		if len(daily) == 0 || !daily[len(daily)-1].Time.Equal(dayStart.UTC()) {
+			daily = append(daily, ForecastEntry{
+				Time:    dayStart.UTC(),
+				TempMin: h.TempMin,
+				TempMax: h.TempMax,
+			})
+			count, tempSum, windSum, humiditySum = 0, 0, 0, 0
+			middayDistance = 24
+		}
+
+		d := &daily[len(daily)-1]
+		count++
+		tempSum += h.Temperature
+		windSum += h.WindSpeed
+		humiditySum += h.Humidity
+		if h.TempMin < d.TempMin {
+			d.TempMin = h.TempMin
+		}
+		if h.TempMax > d.TempMax {
+			d.TempMax = h.TempMax
+		}
+		if h.PrecipitationProbability > d.PrecipitationProbability {
+			d.PrecipitationProbability = h.PrecipitationProbability
+		}
+		if dist := abs(local.Hour() - 12); dist < middayDistance {
+			middayDistance = dist
+			d.ConditionCode = h.ConditionCode
+			d.Description = h.Description
+		}
+
+		d.Temperature = tempSum / float32(count)
+		d.WindSpeed = windSum / float32(count)
+		d.Humidity = humiditySum / int32(count)
+	}
+	return daily
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
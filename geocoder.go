@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"grpc-client/config"
+)
+
+// City é uma cidade encontrada pela busca de nomes
+type City struct {
+	Name        string
+	Country     string
+	Region      string
+	Coordinates Coordinates
+}
+
+// Geocoder é a interface implementada pelas fontes de busca de cidades.
+// A busca alimenta o autocompletar do frontend, então deve ser rápida e
+// tolerar textos incompletos (ex.: "sao pa").
+type Geocoder interface {
+	// Name retorna o identificador da fonte (ex.: "offline").
+	Name() string
+	// SearchCities retorna até limit cidades, da mais para a menos relevante.
+	SearchCities(ctx context.Context, query string, limit int) ([]City, error)
+}
+
+// Nomes das fontes de busca de cidades aceitas na configuração
+const (
+	geocoderOffline     = "offline"
+	geocoderOpenWeather = "openweather"
+)
+
+// Limites da quantidade de resultados da busca de cidades
+const (
+	defaultSearchLimit = 5
+	maxSearchLimit     = 20
+)
+
+// searchLimit valida a quantidade de resultados pedida pelo cliente, aplicando o padrão quando zero
+func searchLimit(limit int32) (int, error) {
+	if limit == 0 {
+		return defaultSearchLimit, nil
+	}
+	if limit < 1 || limit > maxSearchLimit {
+		return 0, &invalidArgumentError{
+			Field:       "limit",
+			Description: fmt.Sprintf("limite de %d resultados fora do intervalo de 1 a %d", limit, maxSearchLimit),
+		}
+	}
+	return int(limit), nil
+}
+
+// newGeocoder cria a fonte de busca de cidades selecionada pela configuração de inicialização
+func newGeocoder(cfg *config.GRPCConfig, upstreams *upstreamSet) (Geocoder, error) {
+	switch cfg.Geocoder {
+	case geocoderOffline:
+		return newOfflineGeocoder()
+	case geocoderOpenWeather:
+		key, err := loadOpenWeatherKey(context.Background(), cfg.OpenWeatherKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return newOpenWeatherGeocoder(key, cfg.OpenWeatherGeoURL, newOpenWeatherClient("openweather-geo", cfg, upstreams)), nil
+	default:
+		return nil, fmt.Errorf("fonte de busca de cidades desconhecida: %q", cfg.Geocoder)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Índice de cidades embutido no binário, usado pela busca offline
+//
+//go:embed data/cities.json
+var bundledCities []byte
+
+// offlineGeocoder implementa Geocoder sobre o índice de cidades embutido,
+// sem depender de nenhuma API externa.
+type offlineGeocoder struct {
+	cities []indexedCity
+}
+
+// indexedCity guarda a cidade com os campos já normalizados para a busca
+type indexedCity struct {
+	City
+	population int64
+	name       string // nome normalizado (ex.: "sao jose dos campos")
+	region     string // região normalizada
+}
+
+func newOfflineGeocoder() (*offlineGeocoder, error) {
+	var entries []struct {
+		Name       string  `json:"name"`
+		Country    string  `json:"country"`
+		Region     string  `json:"region"`
+		Lat        float64 `json:"lat"`
+		Lon        float64 `json:"lon"`
+		Population int64   `json:"population"`
+	}
+	if err := json.Unmarshal(bundledCities, &entries); err != nil {
+		return nil, fmt.Errorf("índice de cidades inválido: %v", err)
+	}
+
+	g := &offlineGeocoder{cities: make([]indexedCity, 0, len(entries))}
+	for _, e := range entries {
+		g.cities = append(g.cities, indexedCity{
+			City: City{
+				Name:        e.Name,
+				Country:     e.Country,
+				Region:      e.Region,
+				Coordinates: Coordinates{Lat: e.Lat, Lon: e.Lon},
+			},
+			population: e.Population,
+			name:       normalizeCity(e.Name),
+			region:     normalizeCity(e.Region),
+		})
+	}
+	return g, nil
+}
+
+func (g *offlineGeocoder) Name() string {
+	return geocoderOffline
+}
+
+// SearchCities procura o texto no nome das cidades, ignorando acentos e maiúsculas.
+// Um qualificador após a vírgula filtra pelo país ou pela região (ex.: "springfield, us",
+// "sao jose, santa"). Nomes idênticos vêm primeiro, depois os que começam com o texto
+// e por fim os que têm uma palavra começando com ele; empates favorecem as cidades maiores.
+func (g *offlineGeocoder) SearchCities(ctx context.Context, query string, limit int) ([]City, error) {
+	name, qualifier := query, ""
+	if i := strings.Index(query, ","); i >= 0 {
+		name, qualifier = query[:i], query[i+1:]
+	}
+	name, qualifier = normalizeCity(name), normalizeCity(qualifier)
+	if name == "" {
+		return nil, nil
+	}
+
+	type match struct {
+		city *indexedCity
+		rank int
+	}
+	var matches []match
+	for i := range g.cities {
+		c := &g.cities[i]
+		rank := matchRank(c.name, name)
+		if rank < 0 {
+			continue
+		}
+		if qualifier != "" && strings.ToLower(c.Country) != qualifier && !strings.HasPrefix(c.region, qualifier) {
+			continue
+		}
+		matches = append(matches, match{city: c, rank: rank})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		return matches[i].city.population > matches[j].city.population
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	cities := make([]City, 0, len(matches))
+	for _, m := range matches {
+		cities = append(cities, m.city.City)
+	}
+	return cities, nil
+}
+
+// matchRank classifica a correspondência entre o nome da cidade e o texto buscado,
+// ambos normalizados: 0 para nome idêntico, 1 para prefixo do nome, 2 para prefixo
+// de uma das palavras e -1 quando não há correspondência.
+func matchRank(name, query string) int {
+	switch {
+	case name == query:
+		return 0
+	case strings.HasPrefix(name, query):
+		return 1
+	case strings.Contains(name, " "+query):
+		return 2
+	default:
+		return -1
+	}
+}
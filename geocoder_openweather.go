@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Item da resposta do endpoint /direct da API de geocodificação do OpenWeather
+type GeocodingAPIResponse struct {
+	Name    string  `json:"name"`
+	Country string  `json:"country"`
+	State   string  `json:"state"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+// openWeatherGeocoder implementa Geocoder usando a API de geocodificação do OpenWeather.
+// Reaproveita o cliente HTTP do fornecedor de clima, com a mesma chave e o mesmo
+// tratamento de erros, apontado para a URL base da geocodificação.
+type openWeatherGeocoder struct {
+	api *openWeatherProvider
+}
+
+func newOpenWeatherGeocoder(key secretSource, baseURL string, client *upstreamClient) *openWeatherGeocoder {
+	return &openWeatherGeocoder{api: newOpenWeatherProvider(key, baseURL, client)}
+}
+
+func (g *openWeatherGeocoder) Name() string {
+	return geocoderOpenWeather
+}
+
+// SearchCities faz a chamada para o endpoint /direct, que já aceita
+// o formato "cidade,estado,país" e ordena os resultados por relevância
+func (g *openWeatherGeocoder) SearchCities(ctx context.Context, query string, limit int) ([]City, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+
+	body, err := g.api.get(ctx, "/direct", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []GeocodingAPIResponse
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("%w: falha ao decodificar JSON: %v", errUpstreamBadResponse, err)
+	}
+
+	cities := make([]City, 0, len(results))
+	for _, r := range results {
+		cities = append(cities, City{
+			Name:        r.Name,
+			Country:     r.Country,
+			Region:      r.State,
+			Coordinates: Coordinates{Lat: r.Lat, Lon: r.Lon},
+		})
+	}
+	return cities, nil
+}
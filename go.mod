@@ -1,6 +1,6 @@
 module grpc-client
 
-go 1.20
+go 1.21
 
 require google.golang.org/grpc v1.65.0
 
@@ -16,7 +16,7 @@ require (
 require (
 	golang.org/x/net v0.26.0 // indirect
 	golang.org/x/sys v0.21.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
-	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157 // indirect
+	golang.org/x/text v0.16.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157
 	google.golang.org/protobuf v1.34.2
 )
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	pb "grpc-client/web"
+)
+
+// historyProvider envolve outro WeatherProvider, gravando no armazenamento cada
+// observação obtida. Fica abaixo do cache, para registrar apenas as consultas que
+// chegaram ao fornecedor.
+type historyProvider struct {
+	WeatherProvider
+
+	store ObservationStore
+}
+
+func newHistoryProvider(provider WeatherProvider, store ObservationStore) *historyProvider {
+	return &historyProvider{WeatherProvider: provider, store: store}
+}
+
+// CurrentWeather consulta o fornecedor e grava a observação obtida.
+// Uma falha na gravação não impede a resposta ao cliente.
+func (p *historyProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	weather, err := p.WeatherProvider.CurrentWeather(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	if rec, ok := newObservationRecord(weather); ok {
+		if err := p.store.Append(rec); err != nil {
+			log.Printf("Erro ao gravar observação de %s: %v", weather.City, err)
+		}
+	}
+	return weather, nil
+}
+
+// Intervalo padrão do histórico quando o cliente não informa o início
+const defaultHistoryRange = 24 * time.Hour
+
+// historyBucket converte a granularidade pedida pelo cliente na duração de cada ponto da série.
+// Zero indica observações individuais.
+func historyBucket(g pb.Granularity) (time.Duration, pb.Granularity, error) {
+	switch g {
+	case pb.Granularity_GRANULARITY_UNSPECIFIED, pb.Granularity_GRANULARITY_RAW:
+		return 0, pb.Granularity_GRANULARITY_RAW, nil
+	case pb.Granularity_GRANULARITY_HOURLY:
+		return time.Hour, g, nil
+	case pb.Granularity_GRANULARITY_DAILY:
+		return 24 * time.Hour, g, nil
+	default:
+		return 0, 0, &invalidArgumentError{Field: "granularity", Description: fmt.Sprintf("granularidade desconhecida: %d", g)}
+	}
+}
+
+// historyRange valida o intervalo pedido pelo cliente, aplicando os padrões quando zero
+func historyRange(from, to int64, now time.Time) (time.Time, time.Time, error) {
+	end := now
+	if to != 0 {
+		end = time.Unix(to, 0)
+	}
+	start := end.Add(-defaultHistoryRange)
+	if from != 0 {
+		start = time.Unix(from, 0)
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, &invalidArgumentError{Field: "from", Description: "o início do intervalo deve ser anterior ao fim"}
+	}
+	return start, end, nil
+}
+
+// historyPoints monta os pontos da série na unidade pedida. Com bucket maior que zero,
+// as observações são resumidas por intervalo, alinhado ao fuso horário da cidade
+// (para que os dias comecem à meia-noite local).
+func historyPoints(s *citySeries, bucket time.Duration, u Units) []*pb.HistoryPoint {
+	offset := time.Duration(s.timezoneOffset) * time.Second
+
+	var points []*pb.HistoryPoint
+	var point *pb.HistoryPoint
+	var start time.Time
+	var humidity, pressure int64
+	var wind float32
+
+	// flush fecha o ponto atual, calculando as médias acumuladas
+	flush := func() {
+		if point == nil {
+			return
+		}
+		n := point.Samples
+		point.Temperature = convertTemperature(point.Temperature/float32(n), u)
+		point.TempMin = convertTemperature(point.TempMin, u)
+		point.TempMax = convertTemperature(point.TempMax, u)
+		point.Humidity = int32(humidity / int64(n))
+		point.Pressure = int32(pressure / int64(n))
+		point.WindSpeed = convertSpeed(wind/float32(n), u)
+		points = append(points, point)
+		point = nil
+	}
+
+	for _, o := range s.records {
+		t := o.Time
+		if bucket > 0 {
+			t = t.Add(offset).Truncate(bucket).Add(-offset)
+		}
+		if point == nil || !t.Equal(start) {
+			flush()
+			start = t
+			point = &pb.HistoryPoint{Time: t.Unix(), TempMin: o.Temperature, TempMax: o.Temperature}
+			humidity, pressure, wind = 0, 0, 0
+		}
+		point.Samples++
+		point.Temperature += o.Temperature
+		if bucket == 0 {
+			// Observação individual: a mínima e a máxima informadas pelo fornecedor
+			point.TempMin, point.TempMax = o.TempMin, o.TempMax
+		} else {
+			if o.Temperature < point.TempMin {
+				point.TempMin = o.Temperature
+			}
+			if o.Temperature > point.TempMax {
+				point.TempMax = o.Temperature
+			}
+		}
+		humidity += int64(o.Humidity)
+		pressure += int64(o.Pressure)
+		wind += o.WindSpeed
+		point.ConditionCode = o.ConditionCode
+		point.Description = o.Description
+	}
+	flush()
+	return points
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Idiomas em que as descrições do clima podem ser pedidas aos fornecedores.
+// O primeiro é usado quando nenhum idioma suportado se aproxima do pedido.
+var supportedLocales = []language.Tag{
+	language.English,
+	language.BrazilianPortuguese,
+	language.Spanish,
+	language.French,
+	language.German,
+	language.Italian,
+}
+
+var localeMatcher = language.NewMatcher(supportedLocales)
+
+// parseLocale valida o idioma pedido pelo cliente (tag BCP 47, ex.: "pt-BR") e o aproxima
+// do idioma suportado mais próximo (ex.: "en-US" -> "en", "pt-PT" -> "pt-BR").
+// Vazio mantém o idioma padrão do fornecedor.
+func parseLocale(locale string) (string, error) {
+	if locale == "" {
+		return "", nil
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", &invalidArgumentError{
+			Field:       "locale",
+			Description: fmt.Sprintf("idioma inválido %q: use uma tag BCP 47 como pt-BR ou en", locale),
+		}
+	}
+	_, index, _ := localeMatcher.Match(tag)
+	return supportedLocales[index].String(), nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	pb "grpc-client/web"
+)
+
+// Coordinates são coordenadas geográficas em graus decimais
+type Coordinates struct {
+	Lat float64
+	Lon float64
+}
+
+// String formata as coordenadas para logs e mensagens de erro (ex.: "-23.5475,-46.6361")
+func (c Coordinates) String() string {
+	return strconv.FormatFloat(c.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(c.Lon, 'f', -1, 64)
+}
+
+// proto converte as coordenadas para a mensagem gRPC
+func (c Coordinates) proto() *pb.Coordinates {
+	return &pb.Coordinates{Lat: c.Lat, Lon: c.Lon}
+}
+
+// distanceKm calcula a distância em quilômetros entre dois pontos pela fórmula de haversine
+func distanceKm(a, b Coordinates) float64 {
+	const earthRadiusKm = 6371.0
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// weatherRequestQuery monta a consulta a partir do local escolhido no oneof de WeatherRequest
+func weatherRequestQuery(req *pb.WeatherRequest) (WeatherQuery, error) {
+	var q WeatherQuery
+	switch loc := req.Location.(type) {
+	case *pb.WeatherRequest_City:
+		q.City = loc.City
+	case *pb.WeatherRequest_Coordinates:
+		q.Coordinates = coordinatesFromProto(loc.Coordinates)
+	case *pb.WeatherRequest_CityId:
+		q.CityID = loc.CityId
+	case *pb.WeatherRequest_CityCountry:
+		q.City, q.Country = loc.CityCountry.GetCity(), loc.CityCountry.GetCountry()
+	}
+	q.Locale = req.Locale
+	return newQuery(q, req.Units)
+}
+
+// forecastRequestQuery monta a consulta a partir do local escolhido no oneof de ForecastRequest
+func forecastRequestQuery(req *pb.ForecastRequest) (WeatherQuery, error) {
+	var q WeatherQuery
+	switch loc := req.Location.(type) {
+	case *pb.ForecastRequest_City:
+		q.City = loc.City
+	case *pb.ForecastRequest_Coordinates:
+		q.Coordinates = coordinatesFromProto(loc.Coordinates)
+	case *pb.ForecastRequest_CityId:
+		q.CityID = loc.CityId
+	case *pb.ForecastRequest_CityCountry:
+		q.City, q.Country = loc.CityCountry.GetCity(), loc.CityCountry.GetCountry()
+	}
+	q.Locale = req.Locale
+	return newQuery(q, req.Units)
+}
+
+func coordinatesFromProto(c *pb.Coordinates) *Coordinates {
+	if c == nil {
+		return nil
+	}
+	return &Coordinates{Lat: c.Lat, Lon: c.Lon}
+}
+
+// newQuery verifica o local, a unidade e o idioma pedidos pelo cliente e completa a consulta ao fornecedor
+func newQuery(q WeatherQuery, units pb.Units) (WeatherQuery, error) {
+	switch {
+	case q.CityID != 0:
+		if q.CityID < 0 {
+			return WeatherQuery{}, &invalidArgumentError{Field: "city_id", Description: "o ID da cidade deve ser positivo"}
+		}
+	case q.Coordinates != nil:
+		if math.IsNaN(q.Coordinates.Lat) || q.Coordinates.Lat < -90 || q.Coordinates.Lat > 90 {
+			return WeatherQuery{}, &invalidArgumentError{Field: "coordinates.lat", Description: "a latitude deve estar entre -90 e 90"}
+		}
+		if math.IsNaN(q.Coordinates.Lon) || q.Coordinates.Lon < -180 || q.Coordinates.Lon > 180 {
+			return WeatherQuery{}, &invalidArgumentError{Field: "coordinates.lon", Description: "a longitude deve estar entre -180 e 180"}
+		}
+	default:
+		if strings.TrimSpace(q.City) == "" {
+			return WeatherQuery{}, &invalidArgumentError{
+				Field:       "location",
+				Description: "informe a cidade, as coordenadas, o ID da cidade ou a cidade com o país",
+			}
+		}
+		if q.Country != "" && !isCountryCode(q.Country) {
+			return WeatherQuery{}, &invalidArgumentError{
+				Field:       "city_country.country",
+				Description: fmt.Sprintf("código de país inválido %q: use o código ISO 3166-1 de duas letras", q.Country),
+			}
+		}
+	}
+
+	u, err := unitsFromProto(units)
+	if err != nil {
+		return WeatherQuery{}, err
+	}
+	q.Units = u
+
+	if q.Locale, err = parseLocale(q.Locale); err != nil {
+		return WeatherQuery{}, err
+	}
+	return q, nil
+}
+
+// isCountryCode indica se o texto tem o formato de um código ISO 3166-1 alfa-2 (ex.: "BR")
+func isCountryCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
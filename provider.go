@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"grpc-client/config"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Weather representa uma observação de clima já normalizada,
+// independente do fornecedor que a produziu.
+// City, Country, CityID e Coordinates descrevem a cidade resolvida pelo fornecedor,
+// que pode ter um nome diferente do pedido (ex.: "sao paulo" -> "São Paulo").
+type Weather struct {
+	City        string
+	Country     string
+	CityID      int64
+	Coordinates Coordinates
+	Description string
+	Temperature float32
+	Units       Units
+
+	// Detalhes da observação. Temperaturas e vento estão na mesma unidade de Temperature.
+	FeelsLike      float32
+	TempMin        float32
+	TempMax        float32
+	Humidity       int32 // %
+	Pressure       int32 // hPa
+	WindSpeed      float32
+	WindDirection  int32 // graus, de onde o vento sopra (0 = norte)
+	CloudCover     int32 // %
+	Visibility     int32 // metros
+	Sunrise        time.Time
+	Sunset         time.Time
+	TimezoneOffset int // segundos em relação ao UTC
+	ConditionCode  int32
+	Icon           string
+
+	// Procedência dos dados: quando foram observados pelo fornecedor, quando o servidor
+	// os obteve, qual fornecedor os produziu e se a resposta veio do cache.
+	ObservedAt time.Time
+	FetchedAt  time.Time
+	Source     string
+	Cached     bool
+	// Stale indica que o fornecedor falhou e a resposta é a última observação conhecida
+	Stale bool
+}
+
+// WeatherQuery descreve a consulta feita ao fornecedor.
+// O local é identificado por apenas uma das formas, nesta ordem de precedência:
+// CityID, Coordinates ou City (opcionalmente qualificada por Country).
+type WeatherQuery struct {
+	City        string
+	Country     string
+	CityID      int64
+	Coordinates *Coordinates
+	Units       Units
+	// Idioma das descrições (tag BCP 47 já normalizada, ex.: "pt-BR"); vazio usa o padrão do fornecedor
+	Locale string
+}
+
+// String descreve o local consultado, para logs e mensagens de erro
+func (q WeatherQuery) String() string {
+	switch {
+	case q.CityID != 0:
+		return "id " + strconv.FormatInt(q.CityID, 10)
+	case q.Coordinates != nil:
+		return q.Coordinates.String()
+	case q.Country != "":
+		return q.City + "," + strings.ToUpper(q.Country)
+	default:
+		return q.City
+	}
+}
+
+// key gera a chave da consulta, usada pelo cache e pelo observador de assinaturas.
+// Coordenadas são arredondadas para 4 casas (~11 m), para que pequenas variações
+// do mesmo ponto compartilhem a entrada.
+func (q WeatherQuery) key() string {
+	var loc string
+	switch {
+	case q.CityID != 0:
+		loc = "id:" + strconv.FormatInt(q.CityID, 10)
+	case q.Coordinates != nil:
+		loc = fmt.Sprintf("coord:%.4f,%.4f", q.Coordinates.Lat, q.Coordinates.Lon)
+	default:
+		loc = normalizeCity(q.City)
+		if q.Country != "" {
+			loc += "," + strings.ToLower(q.Country)
+		}
+	}
+	return loc + "|" + string(q.Units) + "|" + q.Locale
+}
+
+// WeatherProvider é a interface implementada por qualquer fonte de dados de clima.
+// O servidor gRPC depende apenas dela, o que permite trocar de fornecedor
+// (ou rodar offline com fixtures) sem alterar o restante do código.
+type WeatherProvider interface {
+	// Name retorna o identificador do fornecedor (ex.: "openweather").
+	Name() string
+	// CurrentWeather obtém as condições atuais para a consulta informada.
+	CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error)
+	// Forecast obtém a previsão horária e diária para os próximos dias.
+	Forecast(ctx context.Context, q WeatherQuery, days int) (*Forecast, error)
+}
+
+// Nomes dos fornecedores aceitos na configuração
+const (
+	providerOpenWeather = "openweather"
+	providerFixture     = "fixture"
+)
+
+// newProvider cria o fornecedor de clima selecionado pela configuração de inicialização.
+// Com mais de um fornecedor, eles formam uma cadeia com fallback (chainProvider).
+func newProvider(cfg *config.GRPCConfig, upstreams *upstreamSet) (WeatherProvider, error) {
+	names := cfg.Providers()
+	providers := make([]WeatherProvider, 0, len(names))
+	for _, name := range names {
+		p, err := newNamedProvider(cfg, name, upstreams)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+	switch len(providers) {
+	case 0:
+		return nil, fmt.Errorf("nenhum fornecedor de clima configurado")
+	case 1:
+		return providers[0], nil
+	default:
+		return newChainProvider(providers), nil
+	}
+}
+
+// newNamedProvider cria um único fornecedor de clima pelo nome
+func newNamedProvider(cfg *config.GRPCConfig, name string, upstreams *upstreamSet) (WeatherProvider, error) {
+	switch name {
+	case providerOpenWeather:
+		key, err := loadOpenWeatherKey(context.Background(), cfg.OpenWeatherKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return newOpenWeatherProvider(key, cfg.OpenWeatherURL, newOpenWeatherClient(providerOpenWeather, cfg, upstreams)), nil
+	case providerFixture:
+		return newFixtureProvider(cfg.FixturesDir)
+	default:
+		return nil, fmt.Errorf("fornecedor de clima desconhecido: %q", name)
+	}
+}
+
+// normalizeCity gera uma chave estável para o nome da cidade:
+// remove acentos, espaços extras e converte para minúsculas (ex.: "São  Paulo" -> "sao paulo").
+func normalizeCity(city string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	s, _, err := transform.String(t, city)
+	if err != nil {
+		s = city
+	}
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Parâmetros da pontuação de saúde dos fornecedores da cadeia
+const (
+	// Peso da chamada mais recente nas médias móveis de latência e de erros
+	healthAlpha = 0.2
+	// Latência a partir da qual o fornecedor perde pontos proporcionalmente
+	healthLatencyTarget = time.Second
+	// Meia-vida da taxa de erros sem novas chamadas: um fornecedor rebaixado, que deixa
+	// de ser tentado, recupera a pontuação com o tempo e volta a ter a sua vez
+	healthRecoveryHalfLife = time.Minute
+)
+
+// providerHealth acompanha a latência e a taxa de falhas de um fornecedor
+// por médias móveis exponenciais, que dão mais peso às chamadas recentes.
+type providerHealth struct {
+	mu        sync.Mutex
+	latency   time.Duration
+	errorRate float64
+	requests  uint64
+	failures  uint64
+	lastCall  time.Time
+}
+
+// observe registra o resultado de uma chamada
+func (h *providerHealth) observe(latency time.Duration, failed bool, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	failure := 0.0
+	if failed {
+		failure = 1
+		h.failures++
+	}
+	if h.requests == 0 {
+		h.latency, h.errorRate = latency, failure
+	} else {
+		h.latency = time.Duration(healthAlpha*float64(latency) + (1-healthAlpha)*float64(h.latency))
+		h.errorRate = healthAlpha*failure + (1-healthAlpha)*h.decayedErrorRate(now)
+	}
+	h.requests++
+	h.lastCall = now
+}
+
+// score retorna a saúde do fornecedor entre 0 e 1: a fração de chamadas bem-sucedidas,
+// reduzida proporcionalmente quando a latência passa de healthLatencyTarget.
+// Sem chamadas registradas, o fornecedor é considerado saudável.
+func (h *providerHealth) score(now time.Time) float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.requests == 0 {
+		return 1
+	}
+	s := 1 - h.decayedErrorRate(now)
+	if h.latency > healthLatencyTarget {
+		s *= float64(healthLatencyTarget) / float64(h.latency)
+	}
+	return s
+}
+
+// decayedErrorRate reduz a taxa de erros pela metade a cada healthRecoveryHalfLife sem chamadas
+func (h *providerHealth) decayedErrorRate(now time.Time) float64 {
+	idle := now.Sub(h.lastCall)
+	if idle <= 0 {
+		return h.errorRate
+	}
+	return h.errorRate * math.Pow(0.5, float64(idle)/float64(healthRecoveryHalfLife))
+}
+
+// chainMember é um fornecedor da cadeia com sua saúde
+type chainMember struct {
+	provider WeatherProvider
+	priority int // posição na configuração (0 = preferido)
+	health   providerHealth
+}
+
+// breakerReporter é implementado pelos fornecedores cujas chamadas passam por um circuit breaker
+// (o do cliente HTTP upstream). A cadeia não tem breaker próprio, para que dois breakers não
+// abram e fechem de forma independente: ela só consulta este para ordenar as tentativas.
+type breakerReporter interface {
+	Breaker() *circuitBreaker
+}
+
+// breaker retorna o circuit breaker do fornecedor, ou nil se ele não tiver um
+func (m *chainMember) breaker() *circuitBreaker {
+	if r, ok := m.provider.(breakerReporter); ok {
+		return r.Breaker()
+	}
+	return nil
+}
+
+// chainProvider implementa WeatherProvider sobre uma lista ordenada de fornecedores.
+// Cada consulta tenta os fornecedores do mais ao menos saudável até um responder;
+// empates (diferença menor que 0.1 na pontuação) mantêm a ordem da configuração.
+// Fornecedores com o circuito aberto ficam por último; enquanto o breaker espera,
+// o cliente HTTP recusa a chamada de imediato e a vez passa ao próximo.
+// A resposta identifica o fornecedor escolhido no campo Source.
+type chainProvider struct {
+	members []*chainMember
+	now     func() time.Time
+}
+
+func newChainProvider(providers []WeatherProvider) *chainProvider {
+	c := &chainProvider{now: time.Now}
+	for i, p := range providers {
+		c.members = append(c.members, &chainMember{provider: p, priority: i})
+	}
+	return c
+}
+
+// Name lista os fornecedores na ordem da configuração (ex.: "openweather,fixture")
+func (c *chainProvider) Name() string {
+	names := make([]string, 0, len(c.members))
+	for _, m := range c.members {
+		names = append(names, m.provider.Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func (c *chainProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	var weather *Weather
+	err := c.run(ctx, func(p WeatherProvider) error {
+		var err error
+		weather, err = p.CurrentWeather(ctx, q)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return weather, nil
+}
+
+func (c *chainProvider) Forecast(ctx context.Context, q WeatherQuery, days int) (*Forecast, error) {
+	var forecast *Forecast
+	err := c.run(ctx, func(p WeatherProvider) error {
+		var err error
+		forecast, err = p.Forecast(ctx, q, days)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return forecast, nil
+}
+
+// ordered retorna os membros na ordem em que devem ser tentados: os que o breaker recusaria
+// agora por último e, entre os demais, os mais saudáveis primeiro. Passado o cooldown, o
+// fornecedor volta à ordem normal e a sua vez serve de chamada de teste do breaker.
+func (c *chainProvider) ordered() []*chainMember {
+	type ranked struct {
+		member *chainMember
+		open   bool
+		score  float64
+	}
+	now := c.now()
+	ranks := make([]ranked, 0, len(c.members))
+	for _, m := range c.members {
+		b := m.breaker()
+		ranks = append(ranks, ranked{
+			member: m,
+			open:   b != nil && b.Blocked(),
+			score:  math.Round(m.health.score(now) * 10),
+		})
+	}
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].open != ranks[j].open {
+			return !ranks[i].open
+		}
+		return ranks[i].score > ranks[j].score
+	})
+
+	out := make([]*chainMember, 0, len(ranks))
+	for _, r := range ranks {
+		out = append(out, r.member)
+	}
+	return out
+}
+
+// run chama os fornecedores em ordem até um deles responder sem erro.
+// Só falhas do fornecedor (indisponível, limite de uso, resposta inválida) contam contra
+// a saúde; um "cidade não encontrada" mostra que o fornecedor está respondendo.
+// Apenas as falhas do próprio fornecedor passam a vez ao próximo (ver failover); os demais
+// erros são a resposta da consulta. Se todos falharem, devolve o erro do fornecedor
+// de maior prioridade na configuração.
+func (c *chainProvider) run(ctx context.Context, call func(WeatherProvider) error) error {
+	var firstErr error
+	firstPriority := len(c.members)
+
+	for _, m := range c.ordered() {
+		start := c.now()
+		err := call(m.provider)
+		if ctx.Err() != nil {
+			// O cliente desistiu: a falha não diz nada sobre o fornecedor
+			if err == nil {
+				err = ctx.Err()
+			}
+			return err
+		}
+
+		failed := err != nil && isUpstreamFailure(err)
+		end := c.now()
+		m.health.observe(end.Sub(start), failed, end)
+		if err == nil || !failover(err) {
+			return err
+		}
+
+		log.Printf("Fornecedor %s falhou, tentando o próximo da cadeia: %v", m.provider.Name(), err)
+		if m.priority < firstPriority {
+			firstErr, firstPriority = err, m.priority
+		}
+	}
+
+	return firstErr
+}
+
+// failover indica se o erro justifica tentar o próximo fornecedor da cadeia: só as falhas do
+// próprio fornecedor, ou seja, os códigos Unavailable (inclusive o circuito aberto) e Internal,
+// e o limite de uso ou a cota da chave esgotados, que valem apenas para aquele fornecedor.
+// Uma cidade inexistente ou um argumento inválido valem para qualquer fornecedor, e outro
+// não deve responder no lugar do primeiro.
+func failover(err error) bool {
+	if errors.Is(err, errUpstreamRateLimited) {
+		return true
+	}
+	switch status.Code(toStatus(err, "")) {
+	case codes.Unavailable, codes.Internal:
+		return true
+	default:
+		return false
+	}
+}
+
+// providerStatus descreve um fornecedor da cadeia no endpoint administrativo
+type providerStatus struct {
+	Name      string  `json:"name"`
+	Priority  int     `json:"priority"`
+	Breaker   string  `json:"breaker,omitempty"` // vazio para fornecedores sem circuit breaker
+	Score     float64 `json:"score"`
+	LatencyMs int64   `json:"latencyMs"`
+	ErrorRate float64 `json:"errorRate"`
+	Requests  uint64  `json:"requests"`
+	Failures  uint64  `json:"failures"`
+}
+
+// Stats retorna o estado de cada fornecedor, na ordem em que seriam tentados agora
+func (c *chainProvider) Stats() []providerStatus {
+	now := c.now()
+	out := make([]providerStatus, 0, len(c.members))
+	for _, m := range c.ordered() {
+		score := m.health.score(now)
+		var breaker string
+		if b := m.breaker(); b != nil {
+			breaker = b.State().String()
+		}
+		m.health.mu.Lock()
+		out = append(out, providerStatus{
+			Name:      m.provider.Name(),
+			Priority:  m.priority,
+			Breaker:   breaker,
+			Score:     math.Round(score*1000) / 1000,
+			LatencyMs: m.health.latency.Milliseconds(),
+			ErrorRate: math.Round(m.health.decayedErrorRate(now)*1000) / 1000,
+			Requests:  m.health.requests,
+			Failures:  m.health.failures,
+		})
+		m.health.mu.Unlock()
+	}
+	return out
+}
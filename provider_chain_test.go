@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// stubProvider responde sempre com o erro informado ou, sem erro, com o clima da cidade consultada
+type stubProvider struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (p *stubProvider) Name() string {
+	return p.name
+}
+
+func (p *stubProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &Weather{City: q.City, Source: p.name}, nil
+}
+
+func (p *stubProvider) Forecast(ctx context.Context, q WeatherQuery, days int) (*Forecast, error) {
+	p.calls++
+	return nil, p.err
+}
+
+func TestChainProviderFailover(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantSource string // vazio quando o erro do primeiro fornecedor é a resposta
+	}{
+		{"indisponível", fmt.Errorf("%w: HTTP 503", errUpstreamUnavailable), "fixture"},
+		{"circuito aberto", fmt.Errorf("%w: circuito aberto para openweather", errUpstreamUnavailable), "fixture"},
+		{"resposta inválida", fmt.Errorf("%w: sem main", errUpstreamBadResponse), "fixture"},
+		{"chave recusada", fmt.Errorf("%w: HTTP 401", errUpstreamUnauthorized), "fixture"},
+		{"429 do fornecedor", withRetryAfter(fmt.Errorf("%w: HTTP 429", errUpstreamRateLimited), 30*time.Second), "fixture"},
+		{"cota da chave esgotada", fmt.Errorf("%w: cota de openweather esgotada", errUpstreamRateLimited), "fixture"},
+		{"cidade não encontrada", fmt.Errorf("%w: London", errCityNotFound), ""},
+		{"argumento inválido", &invalidArgumentError{Field: "city", Description: "vazio"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := &stubProvider{name: "openweather", err: tt.err}
+			second := &stubProvider{name: "fixture"}
+			chain := newChainProvider([]WeatherProvider{first, second})
+
+			weather, err := chain.CurrentWeather(context.Background(), WeatherQuery{City: "London"})
+			if tt.wantSource == "" {
+				if !errors.Is(err, tt.err) || second.calls != 0 {
+					t.Errorf("erro = %v com %d chamadas ao segundo, quer o erro do primeiro sem tentar o segundo", err, second.calls)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CurrentWeather: %v, quer a resposta do segundo fornecedor", err)
+			}
+			if weather.Source != tt.wantSource {
+				t.Errorf("source = %q, quer %q", weather.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestChainProviderAllFail(t *testing.T) {
+	first := &stubProvider{name: "openweather", err: fmt.Errorf("%w: cota esgotada", errUpstreamRateLimited)}
+	second := &stubProvider{name: "fixture", err: fmt.Errorf("%w: HTTP 500", errUpstreamUnavailable)}
+	chain := newChainProvider([]WeatherProvider{first, second})
+
+	// Todos falharam: vale o erro do fornecedor de maior prioridade na configuração
+	_, err := chain.CurrentWeather(context.Background(), WeatherQuery{City: "London"})
+	if !errors.Is(err, errUpstreamRateLimited) {
+		t.Errorf("erro = %v, quer o do primeiro fornecedor", err)
+	}
+}
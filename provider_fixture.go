@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// fixtureProvider implementa WeatherProvider lendo respostas prontas do disco.
+// Cada cidade corresponde a um arquivo JSON no formato da API do OpenWeather
+// (ex.: "São Paulo" -> fixtures/sao_paulo.json, e fixtures/forecast/sao_paulo.json
+// para a previsão), o que torna as respostas determinísticas e permite rodar
+// o servidor sem acesso à internet. Consultas por ID ou coordenadas usam o
+// índice montado com os dados de cidade de cada fixture.
+type fixtureProvider struct {
+	dir    string
+	cities []fixtureCity
+}
+
+// fixtureCity identifica a cidade de uma fixture de clima atual
+type fixtureCity struct {
+	name        string // nome do arquivo sem extensão (ex.: "sao_paulo")
+	id          int64
+	country     string
+	coordinates Coordinates
+}
+
+// Distância máxima entre as coordenadas pedidas e a cidade de uma fixture
+const fixtureMaxDistanceKm = 50
+
+func newFixtureProvider(dir string) (*fixtureProvider, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("diretório de fixtures inválido: %v", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("diretório de fixtures inválido: %s não é um diretório", dir)
+	}
+
+	cities, err := indexFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &fixtureProvider{dir: dir, cities: cities}, nil
+}
+
+// indexFixtures lê os dados de cidade (ID, país e coordenadas) das fixtures de clima atual.
+// Fixtures que simulam erros não trazem ID e ficam fora do índice.
+func indexFixtures(dir string) ([]fixtureCity, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar fixtures: %v", err)
+	}
+
+	var cities []fixtureCity
+	for _, file := range files {
+		body, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler fixture: %v", err)
+		}
+		var data WeatherAPIResponse
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, fmt.Errorf("fixture %s inválida: %v", file, err)
+		}
+		if data.ID == 0 || data.Coord == nil {
+			continue
+		}
+		cities = append(cities, fixtureCity{
+			name:        strings.TrimSuffix(filepath.Base(file), ".json"),
+			id:          data.ID,
+			country:     data.Sys.Country,
+			coordinates: Coordinates{Lat: data.Coord.Lat, Lon: data.Coord.Lon},
+		})
+	}
+	return cities, nil
+}
+
+func (p *fixtureProvider) Name() string {
+	return providerFixture
+}
+
+// CurrentWeather lê o arquivo de fixture correspondente à cidade.
+// As fixtures estão em unidades métricas e são convertidas para a unidade pedida;
+// as descrições estão sempre em português, independente do idioma pedido.
+func (p *fixtureProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	body, err := p.read(p.dir, q)
+	if err != nil {
+		return nil, err
+	}
+
+	weather, err := decodeOpenWeather(q.City, body)
+	if err != nil {
+		return nil, err
+	}
+	convertWeather(weather, q.Units)
+	weather.FetchedAt = time.Now().UTC()
+	weather.Source = providerFixture
+	return weather, nil
+}
+
+// Forecast lê o arquivo de previsão correspondente à cidade no subdiretório "forecast"
+func (p *fixtureProvider) Forecast(ctx context.Context, q WeatherQuery, days int) (*Forecast, error) {
+	body, err := p.read(filepath.Join(p.dir, "forecast"), q)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast, err := decodeOpenWeatherForecast(q.City, body, days)
+	if err != nil {
+		return nil, err
+	}
+	convertForecast(forecast, q.Units)
+	forecast.Source = providerFixture
+	return forecast, nil
+}
+
+// resolve encontra o nome do arquivo de fixture correspondente ao local consultado.
+// Coordenadas usam a cidade mais próxima, desde que a menos de fixtureMaxDistanceKm.
+func (p *fixtureProvider) resolve(q WeatherQuery) (string, error) {
+	switch {
+	case q.CityID != 0:
+		for _, c := range p.cities {
+			if c.id == q.CityID {
+				return c.name, nil
+			}
+		}
+	case q.Coordinates != nil:
+		best, bestDistance := "", float64(fixtureMaxDistanceKm)
+		for _, c := range p.cities {
+			if d := distanceKm(*q.Coordinates, c.coordinates); d <= bestDistance {
+				best, bestDistance = c.name, d
+			}
+		}
+		if best != "" {
+			return best, nil
+		}
+	default:
+		name := strings.ReplaceAll(normalizeCity(q.City), " ", "_")
+		// O nome vira caminho de arquivo: separadores e ".." permitiriam ler fora do diretório
+		if name == "" || strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+			break
+		}
+		if q.Country == "" {
+			return name, nil
+		}
+		for _, c := range p.cities {
+			if c.name == name && strings.EqualFold(c.country, q.Country) {
+				return name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%w: não há fixture para %s", errCityNotFound, q)
+}
+
+// read carrega o arquivo de fixture do local consultado dentro do diretório informado
+func (p *fixtureProvider) read(dir string, q WeatherQuery) ([]byte, error) {
+	name, err := p.resolve(q)
+	if err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: não há fixture para %s", errCityNotFound, q)
+		}
+		return nil, fmt.Errorf("falha ao ler fixture: %v", err)
+	}
+
+	// Uma fixture pode simular um erro do OpenWeather (ex.: {"cod": "429", "message": "..."})
+	if err := checkOpenWeatherStatus(http.StatusOK, "", body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
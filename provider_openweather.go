@@ -0,0 +1,400 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"grpc-client/config"
+)
+
+// Estrutura para resposta da API OpenWeather.
+// Temp e Coord são ponteiros para distinguir campos ausentes de valores zero (0°C, 0°N 0°L).
+type WeatherAPIResponse struct {
+	Cod     openWeatherCode   `json:"cod"`
+	Message string            `json:"message"`
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Coord   *openWeatherCoord `json:"coord"`
+	Sys     struct {
+		Country string `json:"country"`
+		Sunrise int64  `json:"sunrise"`
+		Sunset  int64  `json:"sunset"`
+	} `json:"sys"`
+	Main struct {
+		Temp      *float32 `json:"temp"`
+		FeelsLike float32  `json:"feels_like"`
+		TempMin   float32  `json:"temp_min"`
+		TempMax   float32  `json:"temp_max"`
+		Pressure  int32    `json:"pressure"`
+		Humidity  int32    `json:"humidity"`
+	} `json:"main"`
+	Weather []struct {
+		ID          int32  `json:"id"`
+		Description string `json:"description"`
+		Icon        string `json:"icon"`
+	} `json:"weather"`
+	Wind struct {
+		Speed float32 `json:"speed"`
+		Deg   int32   `json:"deg"`
+	} `json:"wind"`
+	Clouds struct {
+		All int32 `json:"all"`
+	} `json:"clouds"`
+	Visibility int32 `json:"visibility"`
+	Timezone   int   `json:"timezone"`
+	Dt         int64 `json:"dt"`
+}
+
+// Coordenadas no formato do OpenWeather
+type openWeatherCoord struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Corpo das respostas de erro do OpenWeather (ex.: {"cod": "404", "message": "city not found"})
+type openWeatherErrorResponse struct {
+	Cod     openWeatherCode `json:"cod"`
+	Message string          `json:"message"`
+}
+
+// openWeatherCode é o campo "cod" do OpenWeather, que chega como número
+// em alguns endpoints (/weather) e como texto em outros (/forecast e erros).
+type openWeatherCode int
+
+func (c *openWeatherCode) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*c = openWeatherCode(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("campo cod inválido: %s", data)
+	}
+	if s == "" {
+		*c = 0
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("campo cod inválido: %q", s)
+	}
+	*c = openWeatherCode(n)
+	return nil
+}
+
+// Estrutura para resposta do endpoint /forecast do OpenWeather (intervalos de 3 horas)
+type ForecastAPIResponse struct {
+	Cod     openWeatherCode `json:"cod"`
+	Message string          `json:"message"`
+	List    []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			Temp     float32 `json:"temp"`
+			TempMin  float32 `json:"temp_min"`
+			TempMax  float32 `json:"temp_max"`
+			Humidity int32   `json:"humidity"`
+		} `json:"main"`
+		Weather []struct {
+			ID          int32  `json:"id"`
+			Description string `json:"description"`
+		} `json:"weather"`
+		Wind struct {
+			Speed float32 `json:"speed"`
+		} `json:"wind"`
+		Pop float32 `json:"pop"`
+	} `json:"list"`
+	City struct {
+		ID       int64            `json:"id"`
+		Name     string           `json:"name"`
+		Coord    openWeatherCoord `json:"coord"`
+		Country  string           `json:"country"`
+		Timezone int              `json:"timezone"`
+	} `json:"city"`
+}
+
+// openWeatherProvider implementa WeatherProvider usando a API do OpenWeatherMap
+type openWeatherProvider struct {
+	apiKey  secretSource
+	baseURL string
+	client  *upstreamClient
+}
+
+func newOpenWeatherProvider(key secretSource, baseURL string, client *upstreamClient) *openWeatherProvider {
+	return &openWeatherProvider{
+		apiKey:  key,
+		baseURL: baseURL,
+		client:  client,
+	}
+}
+
+// Breaker retorna o circuit breaker do cliente HTTP do OpenWeather
+func (p *openWeatherProvider) Breaker() *circuitBreaker {
+	return p.client.breaker
+}
+
+// newOpenWeatherClient cria o cliente HTTP de uma API do OpenWeather. As APIs de clima e de
+// geocodificação usam a mesma chave e, por isso, dividem a mesma cota.
+func newOpenWeatherClient(name string, cfg *config.GRPCConfig, upstreams *upstreamSet) *upstreamClient {
+	quota := upstreams.quota(providerOpenWeather, cfg.OpenWeatherPerMinute, cfg.OpenWeatherPerDay, cfg.QuotaWait.Std())
+	return upstreams.client(name, cfg, quota, "appid")
+}
+
+func (p *openWeatherProvider) Name() string {
+	return providerOpenWeather
+}
+
+// CurrentWeather faz a chamada para o endpoint /weather do OpenWeather
+func (p *openWeatherProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	params := locationParams(q)
+	params.Set("units", string(q.Units))
+
+	body, err := p.get(ctx, "/weather", params)
+	if err != nil {
+		return nil, err
+	}
+
+	weather, err := decodeOpenWeather(q.City, body)
+	if err != nil {
+		return nil, err
+	}
+	weather.Units = q.Units
+	weather.FetchedAt = time.Now().UTC()
+	weather.Source = providerOpenWeather
+	return weather, nil
+}
+
+// Forecast faz a chamada para o endpoint /forecast do OpenWeather,
+// limitando a quantidade de intervalos de 3 horas ao horizonte pedido.
+func (p *openWeatherProvider) Forecast(ctx context.Context, q WeatherQuery, days int) (*Forecast, error) {
+	params := locationParams(q)
+	params.Set("units", string(q.Units))
+	params.Set("cnt", strconv.Itoa(days*8))
+
+	body, err := p.get(ctx, "/forecast", params)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast, err := decodeOpenWeatherForecast(q.City, body, days)
+	if err != nil {
+		return nil, err
+	}
+	forecast.Units = q.Units
+	forecast.Source = providerOpenWeather
+	return forecast, nil
+}
+
+// locationParams converte o local da consulta nos parâmetros aceitos pelo OpenWeather:
+// id, lat/lon ou q (com o país, quando informado, no formato "cidade,BR"), além do idioma
+func locationParams(q WeatherQuery) url.Values {
+	params := url.Values{}
+	switch {
+	case q.CityID != 0:
+		params.Set("id", strconv.FormatInt(q.CityID, 10))
+	case q.Coordinates != nil:
+		params.Set("lat", strconv.FormatFloat(q.Coordinates.Lat, 'f', -1, 64))
+		params.Set("lon", strconv.FormatFloat(q.Coordinates.Lon, 'f', -1, 64))
+	case q.Country != "":
+		params.Set("q", q.City+","+q.Country)
+	default:
+		params.Set("q", q.City)
+	}
+	if q.Locale != "" {
+		params.Set("lang", openWeatherLang(q.Locale))
+	}
+	return params
+}
+
+// openWeatherLang converte a tag BCP 47 para o código de idioma do OpenWeather,
+// que usa sublinhado e minúsculas para variantes regionais (ex.: "pt-BR" -> "pt_br")
+func openWeatherLang(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "-", "_"))
+}
+
+// get executa uma requisição GET ao OpenWeather e retorna o corpo da resposta
+func (p *openWeatherProvider) get(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	// Monta a URL da API com os parâmetros codificados e a chave de API
+	params.Set("appid", p.apiKey.Value())
+	reqURL := p.baseURL + path + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar requisição: %v", err)
+	}
+
+	// Faz a requisição HTTP para a API do OpenWeather, com timeout, novas tentativas e circuit breaker
+	resp, err := p.client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Resposta da API OpenWeather para %s: HTTP %d, %d bytes", path, resp.StatusCode, len(resp.Body))
+
+	if err := checkOpenWeatherStatus(resp.StatusCode, resp.Header.Get("Retry-After"), resp.Body); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// checkOpenWeatherStatus converte respostas de erro do OpenWeather em erros tipados.
+// O status HTTP prevalece; o campo "cod" do corpo é usado quando o HTTP indica sucesso
+// mas o corpo informa um erro.
+func checkOpenWeatherStatus(statusCode int, retryAfter string, body []byte) error {
+	var errBody openWeatherErrorResponse
+	_ = json.Unmarshal(body, &errBody) // O corpo pode não ser JSON (ex.: página de erro de um proxy)
+
+	code := statusCode
+	if code >= 200 && code < 300 && errBody.Cod != 0 {
+		code = int(errBody.Cod)
+	}
+	if code >= 200 && code < 300 {
+		return nil
+	}
+
+	err := &upstreamStatusError{StatusCode: code, Message: errBody.Message}
+	switch {
+	case code == http.StatusNotFound:
+		err.kind = errCityNotFound
+	case code == http.StatusBadRequest:
+		// O OpenWeather responde 400 para cidades em branco ou parâmetros inválidos
+		return &invalidArgumentError{Field: "city", Description: fmt.Sprintf("recusada pelo fornecedor: %s", errBody.Message)}
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		err.kind = errUpstreamUnauthorized
+	case code == http.StatusTooManyRequests:
+		err.kind = errUpstreamRateLimited
+		return withRetryAfter(err, parseRetryAfter(retryAfter, time.Now()))
+	case code >= 500:
+		err.kind = errUpstreamUnavailable
+	default:
+		err.kind = errUpstreamBadResponse
+	}
+	return err
+}
+
+// parseRetryAfter interpreta o cabeçalho Retry-After, em segundos ou como data HTTP
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// decodeOpenWeather converte o JSON no formato do OpenWeather para Weather, sem alterar as unidades.
+// É compartilhada com o fornecedor de fixtures, que usa o mesmo formato em disco.
+// O nome da cidade vem da resposta; o nome pedido (city) só aparece nas mensagens de erro.
+func decodeOpenWeather(city string, body []byte) (*Weather, error) {
+	var weatherData WeatherAPIResponse
+	if err := json.Unmarshal(body, &weatherData); err != nil {
+		return nil, fmt.Errorf("%w: falha ao decodificar JSON para %s: %v", errUpstreamBadResponse, city, err)
+	}
+
+	// Campos obrigatórios: sem eles a resposta não descreve o clima nem o local resolvido
+	if weatherData.Main.Temp == nil {
+		return nil, fmt.Errorf("%w: campo main.temp ausente para %s", errUpstreamBadResponse, city)
+	}
+	if len(weatherData.Weather) == 0 {
+		return nil, fmt.Errorf("%w: lista weather vazia para %s", errUpstreamBadResponse, city)
+	}
+	if weatherData.Name == "" {
+		return nil, fmt.Errorf("%w: campo name ausente para %s", errUpstreamBadResponse, city)
+	}
+	if weatherData.Coord == nil {
+		return nil, fmt.Errorf("%w: campo coord ausente para %s", errUpstreamBadResponse, city)
+	}
+
+	// Retorna a cidade resolvida, a descrição, a temperatura e os demais detalhes da observação
+	weather := &Weather{
+		City:           weatherData.Name,
+		Country:        weatherData.Sys.Country,
+		CityID:         weatherData.ID,
+		Coordinates:    Coordinates{Lat: weatherData.Coord.Lat, Lon: weatherData.Coord.Lon},
+		Description:    weatherData.Weather[0].Description,
+		Temperature:    *weatherData.Main.Temp,
+		FeelsLike:      weatherData.Main.FeelsLike,
+		TempMin:        weatherData.Main.TempMin,
+		TempMax:        weatherData.Main.TempMax,
+		Humidity:       weatherData.Main.Humidity,
+		Pressure:       weatherData.Main.Pressure,
+		WindSpeed:      weatherData.Wind.Speed,
+		WindDirection:  weatherData.Wind.Deg,
+		CloudCover:     weatherData.Clouds.All,
+		Visibility:     weatherData.Visibility,
+		TimezoneOffset: weatherData.Timezone,
+		ConditionCode:  weatherData.Weather[0].ID,
+		Icon:           weatherData.Weather[0].Icon,
+	}
+	if weatherData.Dt != 0 {
+		weather.ObservedAt = time.Unix(weatherData.Dt, 0).UTC()
+	}
+	if weatherData.Sys.Sunrise != 0 {
+		weather.Sunrise = time.Unix(weatherData.Sys.Sunrise, 0).UTC()
+	}
+	if weatherData.Sys.Sunset != 0 {
+		weather.Sunset = time.Unix(weatherData.Sys.Sunset, 0).UTC()
+	}
+	return weather, nil
+}
+
+// decodeOpenWeatherForecast converte o JSON do endpoint /forecast para Forecast,
+// descartando os intervalos além do horizonte pedido e resumindo-os por dia.
+func decodeOpenWeatherForecast(city string, body []byte, days int) (*Forecast, error) {
+	var forecastData ForecastAPIResponse
+	if err := json.Unmarshal(body, &forecastData); err != nil {
+		return nil, fmt.Errorf("%w: falha ao decodificar JSON: %v", errUpstreamBadResponse, err)
+	}
+	if len(forecastData.List) == 0 {
+		return nil, fmt.Errorf("%w: lista de previsões vazia", errUpstreamBadResponse)
+	}
+
+	if forecastData.City.Name != "" {
+		city = forecastData.City.Name
+	}
+	forecast := &Forecast{
+		City:        city,
+		Country:     forecastData.City.Country,
+		CityID:      forecastData.City.ID,
+		Coordinates: Coordinates{Lat: forecastData.City.Coord.Lat, Lon: forecastData.City.Coord.Lon},
+	}
+	var limit time.Time
+	for _, item := range forecastData.List {
+		t := time.Unix(item.Dt, 0).UTC()
+		if limit.IsZero() {
+			limit = t.Add(time.Duration(days) * 24 * time.Hour)
+		}
+		if !t.Before(limit) {
+			break
+		}
+
+		entry := ForecastEntry{
+			Time:                     t,
+			Temperature:              item.Main.Temp,
+			TempMin:                  item.Main.TempMin,
+			TempMax:                  item.Main.TempMax,
+			PrecipitationProbability: item.Pop,
+			WindSpeed:                item.Wind.Speed,
+			Humidity:                 item.Main.Humidity,
+		}
+		if len(item.Weather) > 0 {
+			entry.ConditionCode = item.Weather[0].ID
+			entry.Description = item.Weather[0].Description
+		}
+		forecast.Hourly = append(forecast.Hourly, entry)
+	}
+
+	forecast.Daily = dailyFromHourly(forecast.Hourly, forecastData.City.Timezone)
+	return forecast, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"grpc-client/config"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validWeatherBody é uma resposta completa do endpoint /weather do OpenWeather
+func validWeatherBody() map[string]interface{} {
+	return map[string]interface{}{
+		"cod":     200,
+		"id":      2643743,
+		"name":    "London",
+		"coord":   map[string]interface{}{"lat": 51.5085, "lon": -0.1257},
+		"sys":     map[string]interface{}{"country": "GB"},
+		"main":    map[string]interface{}{"temp": 14.2, "humidity": 80},
+		"weather": []map[string]interface{}{{"id": 500, "description": "chuva fraca", "icon": "10d"}},
+	}
+}
+
+// without retorna a resposta válida sem o campo informado
+func without(field string) map[string]interface{} {
+	body := validWeatherBody()
+	delete(body, field)
+	return body
+}
+
+// with retorna a resposta válida com o campo informado substituído
+func with(field string, value interface{}) map[string]interface{} {
+	body := validWeatherBody()
+	body[field] = value
+	return body
+}
+
+// newTestOpenWeather cria o fornecedor apontando para um httptest que responde
+// sempre com o status e o corpo informados, sem novas tentativas
+func newTestOpenWeather(t *testing.T, statusCode int, header http.Header, body interface{}) *openWeatherProvider {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("appid") != "test-key" {
+			t.Errorf("appid = %q, quer test-key", r.URL.Query().Get("appid"))
+		}
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(statusCode)
+		switch b := body.(type) {
+		case string:
+			w.Write([]byte(b))
+		default:
+			json.NewEncoder(w).Encode(b)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg := config.DefaultGRPC()
+	cfg.UpstreamRetries = 0
+	return newOpenWeatherProvider(staticSecret("test-key"), srv.URL, newUpstreamClient(providerOpenWeather, &cfg, nil))
+}
+
+func TestOpenWeatherErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     http.Header
+		body       interface{}
+		wantErr    error // categoria esperada (errors.Is); nil para argumento inválido
+		wantCode   codes.Code
+	}{
+		{"HTTP 401", http.StatusUnauthorized, nil, map[string]interface{}{"cod": 401, "message": "Invalid API key"}, errUpstreamUnauthorized, codes.Internal},
+		{"HTTP 404", http.StatusNotFound, nil, map[string]interface{}{"cod": "404", "message": "city not found"}, errCityNotFound, codes.NotFound},
+		{"HTTP 429", http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, map[string]interface{}{"cod": 429}, errUpstreamRateLimited, codes.ResourceExhausted},
+		{"HTTP 500", http.StatusInternalServerError, nil, "", errUpstreamUnavailable, codes.Unavailable},
+		{"HTTP 502 com HTML", http.StatusBadGateway, nil, "<html>Bad Gateway</html>", errUpstreamUnavailable, codes.Unavailable},
+		{"HTTP 503", http.StatusServiceUnavailable, nil, map[string]interface{}{"cod": 503, "message": "busy"}, errUpstreamUnavailable, codes.Unavailable},
+		{"HTTP 400", http.StatusBadRequest, nil, map[string]interface{}{"cod": "400", "message": "Nothing to geocode"}, nil, codes.InvalidArgument},
+		{"cod 404 como texto", http.StatusOK, nil, map[string]interface{}{"cod": "404", "message": "city not found"}, errCityNotFound, codes.NotFound},
+		{"cod 401 como número", http.StatusOK, nil, map[string]interface{}{"cod": 401, "message": "Invalid API key"}, errUpstreamUnauthorized, codes.Internal},
+		{"cod 429 como texto", http.StatusOK, nil, map[string]interface{}{"cod": "429"}, errUpstreamRateLimited, codes.ResourceExhausted},
+		{"cod 500 como número", http.StatusOK, nil, map[string]interface{}{"cod": 500}, errUpstreamUnavailable, codes.Unavailable},
+		{"JSON inválido", http.StatusOK, nil, "{", errUpstreamBadResponse, codes.Internal},
+		{"sem main", http.StatusOK, nil, without("main"), errUpstreamBadResponse, codes.Internal},
+		{"sem main.temp", http.StatusOK, nil, with("main", map[string]interface{}{"humidity": 80}), errUpstreamBadResponse, codes.Internal},
+		{"sem weather", http.StatusOK, nil, without("weather"), errUpstreamBadResponse, codes.Internal},
+		{"weather vazio", http.StatusOK, nil, with("weather", []interface{}{}), errUpstreamBadResponse, codes.Internal},
+		{"sem name", http.StatusOK, nil, without("name"), errUpstreamBadResponse, codes.Internal},
+		{"sem coord", http.StatusOK, nil, without("coord"), errUpstreamBadResponse, codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestOpenWeather(t, tt.statusCode, tt.header, tt.body)
+			weather, err := p.CurrentWeather(context.Background(), WeatherQuery{City: "London", Units: unitsMetric})
+			if err == nil {
+				t.Fatalf("CurrentWeather = %+v, quer erro", weather)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("erro = %v, quer %v", err, tt.wantErr)
+				}
+			} else {
+				var invalid *invalidArgumentError
+				if !errors.As(err, &invalid) {
+					t.Errorf("erro = %v, quer *invalidArgumentError", err)
+				}
+			}
+
+			if code := status.Code(toStatus(err, "London")); code != tt.wantCode {
+				t.Errorf("código gRPC = %v, quer %v", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestOpenWeatherRetryAfter(t *testing.T) {
+	p := newTestOpenWeather(t, http.StatusTooManyRequests, http.Header{"Retry-After": {"30"}}, map[string]interface{}{"cod": 429})
+	_, err := p.CurrentWeather(context.Background(), WeatherQuery{City: "London", Units: unitsMetric})
+
+	var retry *retryAfterError
+	if !errors.As(err, &retry) {
+		t.Fatalf("erro = %v, quer *retryAfterError", err)
+	}
+	if retry.retryAfter.Seconds() != 30 {
+		t.Errorf("retryAfter = %v, quer 30s", retry.retryAfter)
+	}
+}
+
+func TestOpenWeatherSuccess(t *testing.T) {
+	p := newTestOpenWeather(t, http.StatusOK, nil, validWeatherBody())
+	weather, err := p.CurrentWeather(context.Background(), WeatherQuery{City: "london", Units: unitsMetric})
+	if err != nil {
+		t.Fatalf("CurrentWeather: %v", err)
+	}
+
+	// O local vem da resposta, não da grafia pedida
+	if weather.City != "London" || weather.Country != "GB" || weather.CityID != 2643743 {
+		t.Errorf("cidade = %s, %s (%d), quer London, GB (2643743)", weather.City, weather.Country, weather.CityID)
+	}
+	if weather.Coordinates != (Coordinates{Lat: 51.5085, Lon: -0.1257}) {
+		t.Errorf("coordenadas = %v", weather.Coordinates)
+	}
+	if weather.Temperature != 14.2 || weather.Description != "chuva fraca" {
+		t.Errorf("clima = %v, %q, quer 14.2, \"chuva fraca\"", weather.Temperature, weather.Description)
+	}
+	if weather.Source != providerOpenWeather {
+		t.Errorf("source = %q, quer %q", weather.Source, providerOpenWeather)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Bloqueio aplicado quando o fornecedor responde 429 sem Retry-After
+const quotaDefaultBackoff = time.Minute
+
+// tokenBucket é um balde de fichas: comporta até capacity chamadas seguidas e é
+// reabastecido continuamente à razão de capacity fichas por period.
+// O saldo pode ficar negativo, com fichas já reservadas por chamadas na fila.
+type tokenBucket struct {
+	capacity float64
+	period   time.Duration
+	tokens   float64
+	last     time.Time
+}
+
+func newTokenBucket(capacity int, period time.Duration, now time.Time) *tokenBucket {
+	return &tokenBucket{capacity: float64(capacity), period: period, tokens: float64(capacity), last: now}
+}
+
+// refill acrescenta as fichas acumuladas desde a última atualização
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+b.capacity*float64(elapsed)/float64(b.period))
+		b.last = now
+	}
+}
+
+// wait retorna quanto falta para haver uma ficha disponível
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.capacity * float64(b.period))
+}
+
+// quotaStatus descreve a cota de uma chave no endpoint administrativo.
+// Os campos restantes são omitidos quando o limite correspondente está desabilitado.
+type quotaStatus struct {
+	PerMinute       int    `json:"perMinute"`
+	PerDay          int    `json:"perDay"`
+	RemainingMinute *int   `json:"remainingMinute,omitempty"`
+	RemainingDay    *int   `json:"remainingDay,omitempty"`
+	BlockedUntil    string `json:"blockedUntil,omitempty"`
+	Granted         uint64 `json:"granted"`
+	Queued          uint64 `json:"queued"`
+	Rejected        uint64 `json:"rejected"`
+	Throttled       uint64 `json:"throttled"`
+}
+
+// upstreamQuota controla o uso da chave de um fornecedor com um balde por minuto e outro
+// por dia (janela móvel, não o dia do calendário). Uma chamada sem cota espera na fila até
+// maxWait, dentro do prazo do cliente; se não houver cota a tempo, é recusada com
+// errUpstreamRateLimited. Um 429 do fornecedor suspende as chamadas pelo Retry-After.
+// Zero em perMinute ou perDay desabilita o limite correspondente.
+type upstreamQuota struct {
+	name      string
+	perMinute int
+	perDay    int
+	maxWait   time.Duration
+	now       func() time.Time
+
+	mu           sync.Mutex
+	minute, day  *tokenBucket
+	blockedUntil time.Time
+	granted      uint64
+	queued       uint64
+	rejected     uint64
+	throttled    uint64
+}
+
+func newUpstreamQuota(name string, perMinute, perDay int, maxWait time.Duration) *upstreamQuota {
+	q := &upstreamQuota{name: name, perMinute: perMinute, perDay: perDay, maxWait: maxWait, now: time.Now}
+	now := q.now()
+	if perMinute > 0 {
+		q.minute = newTokenBucket(perMinute, time.Minute, now)
+	}
+	if perDay > 0 {
+		q.day = newTokenBucket(perDay, 24*time.Hour, now)
+	}
+	return q
+}
+
+// acquire reserva uma chamada, esperando na fila se preciso. Uma quota nil não limita.
+func (q *upstreamQuota) acquire(ctx context.Context) error {
+	if q == nil {
+		return nil
+	}
+	wait, ok := q.reserve(ctx)
+	if !ok {
+		return withRetryAfter(fmt.Errorf("%w: cota de %s esgotada", errUpstreamRateLimited, q.name), wait)
+	}
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		q.release()
+		return ctx.Err()
+	}
+}
+
+// reserve desconta uma ficha de cada balde e retorna a espera até a chamada poder ser feita.
+// Se a espera passar de maxWait ou do prazo do cliente, nada é descontado e ok é false.
+func (q *upstreamQuota) reserve(ctx context.Context) (wait time.Duration, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	if q.blockedUntil.After(now) {
+		wait = q.blockedUntil.Sub(now)
+	}
+	for _, b := range []*tokenBucket{q.minute, q.day} {
+		if b == nil {
+			continue
+		}
+		b.refill(now)
+		if w := b.wait(); w > wait {
+			wait = w
+		}
+	}
+
+	limit := q.maxWait
+	if deadline, has := ctx.Deadline(); has && deadline.Sub(now) < limit {
+		limit = deadline.Sub(now)
+	}
+	if wait > limit {
+		q.rejected++
+		return wait, false
+	}
+
+	for _, b := range []*tokenBucket{q.minute, q.day} {
+		if b != nil {
+			b.tokens--
+		}
+	}
+	q.granted++
+	if wait > 0 {
+		q.queued++
+	}
+	return wait, true
+}
+
+// release devolve a ficha de uma chamada que desistiu na fila
+func (q *upstreamQuota) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, b := range []*tokenBucket{q.minute, q.day} {
+		if b != nil {
+			b.tokens = math.Min(b.capacity, b.tokens+1)
+		}
+	}
+	q.granted--
+}
+
+// throttle suspende as chamadas depois de um 429 do fornecedor, pelo tempo do Retry-After
+func (q *upstreamQuota) throttle(retryAfter time.Duration) {
+	if q == nil {
+		return
+	}
+	if retryAfter <= 0 {
+		retryAfter = quotaDefaultBackoff
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if until := q.now().Add(retryAfter); until.After(q.blockedUntil) {
+		q.blockedUntil = until
+	}
+	q.throttled++
+}
+
+// Status retorna a cota restante e os contadores atuais
+func (q *upstreamQuota) Status() quotaStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	st := quotaStatus{
+		PerMinute: q.perMinute,
+		PerDay:    q.perDay,
+		Granted:   q.granted,
+		Queued:    q.queued,
+		Rejected:  q.rejected,
+		Throttled: q.throttled,
+	}
+	remaining := func(b *tokenBucket) *int {
+		if b == nil {
+			return nil
+		}
+		b.refill(now)
+		n := int(math.Max(0, math.Floor(b.tokens)))
+		return &n
+	}
+	st.RemainingMinute = remaining(q.minute)
+	st.RemainingDay = remaining(q.day)
+	if q.blockedUntil.After(now) {
+		st.BlockedUntil = q.blockedUntil.UTC().Format(time.RFC3339)
+	}
+	return st
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTestQuota cria uma cota com relógio controlado pelo teste
+func newTestQuota(perMinute, perDay int, maxWait time.Duration) (*upstreamQuota, *time.Time) {
+	now := time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+	q := newUpstreamQuota("openweather", perMinute, perDay, maxWait)
+	q.now = func() time.Time { return now }
+	for _, b := range []*tokenBucket{q.minute, q.day} {
+		if b != nil {
+			b.last = now
+		}
+	}
+	return q, &now
+}
+
+func TestUpstreamQuotaReservations(t *testing.T) {
+	// 60 por minuto: uma ficha por segundo, com espera máxima de 2s na fila
+	q, _ := newTestQuota(60, 0, 2*time.Second)
+	ctx := context.Background()
+	for i := 0; i < 60; i++ {
+		if wait, ok := q.reserve(ctx); !ok || wait != 0 {
+			t.Fatalf("reserva %d = %s, %v, quer imediata", i+1, wait, ok)
+		}
+	}
+
+	// Sem fichas, as reservas seguintes deixam o saldo negativo e esperam cada vez mais
+	tests := []struct {
+		wantWait time.Duration
+		wantOK   bool
+	}{
+		{time.Second, true},
+		{2 * time.Second, true},
+		{3 * time.Second, false}, // passaria de maxWait: recusada sem descontar ficha
+		{3 * time.Second, false},
+	}
+	for i, tt := range tests {
+		wait, ok := q.reserve(ctx)
+		if wait != tt.wantWait || ok != tt.wantOK {
+			t.Errorf("reserva na fila %d = %s, %v, quer %s, %v", i+1, wait, ok, tt.wantWait, tt.wantOK)
+		}
+	}
+	if q.minute.tokens != -2 {
+		t.Errorf("saldo = %v, quer -2 (duas chamadas na fila)", q.minute.tokens)
+	}
+
+	st := q.Status()
+	if st.Granted != 62 || st.Queued != 2 || st.Rejected != 2 || *st.RemainingMinute != 0 {
+		t.Errorf("status = %+v, quer 62 liberadas, 2 na fila, 2 recusadas e nenhuma restante", st)
+	}
+}
+
+func TestUpstreamQuotaRefillAndRelease(t *testing.T) {
+	q, now := newTestQuota(60, 100, time.Minute)
+	ctx := context.Background()
+	for i := 0; i < 62; i++ {
+		q.reserve(ctx)
+	}
+
+	// Uma chamada que desiste na fila devolve a ficha
+	q.release()
+	if q.minute.tokens != -1 || q.day.tokens != 39 {
+		t.Errorf("saldos = %v/%v depois de devolver, quer -1/39", q.minute.tokens, q.day.tokens)
+	}
+
+	// O balde do minuto se recompõe com o tempo, limitado à capacidade
+	*now = now.Add(2 * time.Minute)
+	if wait, ok := q.reserve(ctx); !ok || wait != 0 {
+		t.Errorf("reserva depois de 2 minutos = %s, %v, quer imediata", wait, ok)
+	}
+	if q.minute.tokens != 59 {
+		t.Errorf("saldo do minuto = %v, quer 59", q.minute.tokens)
+	}
+}
+
+func TestUpstreamQuotaDeadline(t *testing.T) {
+	q, now := newTestQuota(1, 0, time.Minute)
+	q.reserve(context.Background())
+
+	// A próxima ficha só chega em 1 minuto, depois do prazo do cliente
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(10*time.Second))
+	defer cancel()
+	if _, ok := q.reserve(ctx); ok {
+		t.Errorf("reserva liberada com espera maior que o prazo do cliente")
+	}
+}
+
+func TestUpstreamQuotaThrottle(t *testing.T) {
+	q, now := newTestQuota(60, 0, 2*time.Second)
+
+	// Um 429 do fornecedor com Retry-After de 30s suspende as chamadas
+	q.throttle(30 * time.Second)
+	err := q.acquire(context.Background())
+	if !errors.Is(err, errUpstreamRateLimited) {
+		t.Fatalf("acquire = %v, quer errUpstreamRateLimited", err)
+	}
+	var retry *retryAfterError
+	if !errors.As(err, &retry) || retry.retryAfter != 30*time.Second {
+		t.Errorf("acquire = %v, quer Retry-After de 30s", err)
+	}
+	if st := q.Status(); st.Throttled != 1 || st.BlockedUntil != now.Add(30*time.Second).Format(time.RFC3339) {
+		t.Errorf("status = %+v, quer bloqueio de 30s", st)
+	}
+
+	// Um Retry-After menor não encurta o bloqueio; sem Retry-After vale quotaDefaultBackoff
+	q.throttle(time.Second)
+	if wait, _ := q.reserve(context.Background()); wait != 30*time.Second {
+		t.Errorf("espera = %s depois de um Retry-After menor, quer 30s", wait)
+	}
+	q.throttle(0)
+	if wait, _ := q.reserve(context.Background()); wait != quotaDefaultBackoff {
+		t.Errorf("espera = %s depois de um 429 sem Retry-After, quer %s", wait, quotaDefaultBackoff)
+	}
+
+	// Terminado o bloqueio, as chamadas voltam a ser liberadas
+	*now = now.Add(quotaDefaultBackoff)
+	if err := q.acquire(context.Background()); err != nil {
+		t.Errorf("acquire depois do bloqueio = %v", err)
+	}
+}
+
+func TestUpstreamQuotaNil(t *testing.T) {
+	var q *upstreamQuota
+	if err := q.acquire(context.Background()); err != nil {
+		t.Errorf("acquire em cota nil = %v, quer nil", err)
+	}
+	q.throttle(time.Second)
+}
@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Metadados gRPC com a identificação do cliente
+const (
+	forwardedForMD = "x-forwarded-for"
+	apiKeyMD       = "x-api-key"
+)
+
+// Prefixo dos métodos do health check, que nunca são limitados
+const healthMethodPrefix = "/grpc.health.v1.Health/"
+
+// UnaryServerInterceptor limita as chamadas simples. As recusas retornam ResourceExhausted
+// com ErrorInfo (no domínio informado) e RetryInfo; os metadados de resposta
+// x-ratelimit-* acompanham todas as chamadas limitadas. Chamadas de origens isentas
+// (como o gateway HTTP, que já limita os seus clientes) não são contadas.
+func (l *Limiter) UnaryServerInterceptor(domain string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+			return handler(ctx, req)
+		}
+		if err := l.check(ctx, domain, func(md metadata.MD) { grpc.SetHeader(ctx, md) }); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor limita a abertura de streams, contando cada uma como uma requisição
+func (l *Limiter) StreamServerInterceptor(domain string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthMethodPrefix) {
+			return handler(srv, ss)
+		}
+		if err := l.check(ss.Context(), domain, func(md metadata.MD) { ss.SetHeader(md) }); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// check identifica o cliente da chamada, conta a requisição e monta o erro da recusa
+func (l *Limiter) check(ctx context.Context, domain string, setHeader func(metadata.MD)) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	if l.isExempt(remoteAddr) {
+		return nil
+	}
+	c := Client{IP: l.clientIP(remoteAddr, strings.Join(md.Get(forwardedForMD), ","))}
+	if keys := md.Get(apiKeyMD); len(keys) > 0 {
+		c.APIKey = keys[0]
+	} else if values := md.Get("authorization"); len(values) > 0 {
+		c.APIKey = bearerToken(values[0])
+	}
+
+	res, err := l.Allow(ctx, c)
+	if err != nil {
+		log.Printf("Erro no limite de requisições (chamada liberada): %v", err)
+	}
+	if res.Limit == 0 {
+		return nil
+	}
+
+	retry := res.RetryAfter(l.now())
+	setHeader(metadata.Pairs(
+		"x-ratelimit-limit", strconv.Itoa(res.Limit),
+		"x-ratelimit-remaining", strconv.Itoa(res.Remaining),
+		"x-ratelimit-reset", strconv.FormatInt(int64(retry/time.Second), 10),
+	))
+	if res.Allowed {
+		return nil
+	}
+
+	st := status.New(codes.ResourceExhausted, "limite de requisições do cliente atingido")
+	withDetails, detailErr := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: "RATE_LIMITED", Domain: domain},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{
+			{Subject: "client", Description: strconv.Itoa(res.Limit) + " requisições por janela"},
+		}},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(retry)},
+	)
+	if detailErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// UnaryClientInterceptor repassa ao servidor gRPC o cliente guardado no contexto (WithClient):
+// o IP no x-forwarded-for e a chave de API no x-api-key. Usado pelo gateway HTTP, para que o
+// servidor gRPC limite cada cliente final, e não o gateway.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(forwardClient(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor é a versão de UnaryClientInterceptor para streams
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(forwardClient(ctx), desc, cc, method, opts...)
+	}
+}
+
+func forwardClient(ctx context.Context) context.Context {
+	c, ok := ClientFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, forwardedForMD, c.IP)
+	if c.APIKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, apiKeyMD, c.APIKey)
+	}
+	return ctx
+}
@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cabeçalho com a chave de API do cliente
+const apiKeyHeader = "X-API-Key"
+
+type clientKey struct{}
+
+// WithClient guarda o cliente no contexto, para ser repassado ao servidor gRPC
+func WithClient(ctx context.Context, c Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// ClientFromContext retorna o cliente guardado por WithClient
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	c, ok := ctx.Value(clientKey{}).(Client)
+	return c, ok
+}
+
+// requestAPIKey retorna a chave do cabeçalho X-API-Key ou, na falta dele, o token
+// bearer do Authorization (que só conta como chave se for uma das chaves aceitas)
+func requestAPIKey(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	return bearerToken(r.Header.Get("Authorization"))
+}
+
+// bearerToken extrai o token de "Bearer <token>"
+func bearerToken(authorization string) string {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// Middleware limita as requisições ao handler. As respostas levam os cabeçalhos
+// X-RateLimit-Limit, X-RateLimit-Remaining e X-RateLimit-Reset (segundos até o fim da janela);
+// as recusadas recebem também o Retry-After e são respondidas por reject.
+// O cliente fica no contexto da requisição (ClientFromContext). Origens isentas não são contadas.
+func (l *Limiter) Middleware(next http.Handler, reject func(http.ResponseWriter, *http.Request, Result)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := Client{
+			IP:     l.clientIP(r.RemoteAddr, r.Header.Get("X-Forwarded-For")),
+			APIKey: requestAPIKey(r),
+		}
+		r = r.WithContext(WithClient(r.Context(), c))
+		if l.isExempt(r.RemoteAddr) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		res, err := l.Allow(r.Context(), c)
+		if err != nil {
+			log.Printf("Erro no limite de requisições (requisição liberada): %v", err)
+		}
+		if res.Limit > 0 {
+			now := l.now()
+			h := w.Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("X-RateLimit-Reset", strconv.FormatInt(int64(res.RetryAfter(now)/time.Second), 10))
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.FormatInt(int64(res.RetryAfter(now)/time.Second), 10))
+				reject(w, r, res)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryWindow é a contagem de uma chave na janela atual
+type memoryWindow struct {
+	count int
+	reset time.Time
+}
+
+// MemoryStore implementa Store em memória, para uma única instância.
+// As janelas encerradas são descartadas periodicamente, durante as próprias contagens.
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: make(map[string]*memoryWindow), now: time.Now}
+}
+
+func (s *MemoryStore) Increment(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now, window)
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.reset) {
+		w = &memoryWindow{reset: now.Truncate(window).Add(window)}
+		s.windows[key] = w
+	}
+	w.count++
+	return w.count, w.reset, nil
+}
+
+// sweep remove as janelas encerradas, no máximo uma vez por janela
+func (s *MemoryStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(s.lastSweep) < window {
+		return
+	}
+	for key, w := range s.windows {
+		if !now.Before(w.reset) {
+			delete(s.windows, key)
+		}
+	}
+	s.lastSweep = now
+}
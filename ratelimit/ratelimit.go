@@ -0,0 +1,228 @@
+// Package ratelimit limita as requisições de cada cliente no gateway HTTP e no servidor gRPC.
+//
+// O cliente é identificado pela chave de API (cabeçalho X-API-Key, token bearer do
+// Authorization ou os metadados gRPC equivalentes),
+// quando ela é uma das chaves aceitas, ou pelo IP de origem. As requisições são contadas em
+// janelas fixas de tempo, alinhadas ao relógio, por um Store: o MemoryStore atende a um único
+// processo; um Store compartilhado (ex.: Redis) permite que várias instâncias dividam o limite.
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"grpc-client/config"
+)
+
+// Store conta as requisições de cada cliente por janela
+type Store interface {
+	// Increment soma uma requisição à janela atual da chave e retorna o total
+	// da janela e o momento em que ela termina.
+	Increment(ctx context.Context, key string, window time.Duration) (count int, reset time.Time, err error)
+}
+
+// Client identifica quem fez a requisição
+type Client struct {
+	IP     string
+	APIKey string // Como enviada pelo cliente; só é usada se estiver entre as chaves aceitas
+}
+
+// Result é a decisão sobre uma requisição. Limit zero indica cliente sem limite.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// RetryAfter retorna a espera até o fim da janela, arredondada para cima em segundos
+func (r Result) RetryAfter(now time.Time) time.Duration {
+	d := r.Reset.Sub(now)
+	if d < time.Second {
+		return time.Second
+	}
+	return (d + time.Second - 1).Truncate(time.Second)
+}
+
+// Limiter aplica os limites por IP e por chave de API
+type Limiter struct {
+	store   Store
+	perIP   int
+	perKey  int
+	window  time.Duration
+	keys    map[string]bool
+	trusted []*net.IPNet
+	exempt  []*net.IPNet
+	now     func() time.Time
+}
+
+// New cria o limitador. Um limite zero não restringe o tipo de cliente correspondente.
+// As requisições de proxies confiáveis (trusted) são atribuídas ao IP informado no X-Forwarded-For;
+// as que chegam de origens isentas (exempt) não são contadas.
+func New(store Store, perIP, perKey int, window time.Duration, keys map[string]bool, trusted, exempt []*net.IPNet) *Limiter {
+	return &Limiter{
+		store:   store,
+		perIP:   perIP,
+		perKey:  perKey,
+		window:  window,
+		keys:    keys,
+		trusted: trusted,
+		exempt:  exempt,
+		now:     time.Now,
+	}
+}
+
+// NewFromConfig cria o limitador com as opções de configuração, contando as requisições em store
+func NewFromConfig(cfg config.RateLimitConfig, store Store) (*Limiter, error) {
+	keys, err := LoadKeys(cfg.APIKeysFile)
+	if err != nil {
+		return nil, err
+	}
+	trusted, err := ParseNetworks(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	exempt, err := ParseNetworks(cfg.RateLimitExempt)
+	if err != nil {
+		return nil, err
+	}
+	return New(store, cfg.RateLimitPerIP, cfg.RateLimitPerKey, cfg.RateLimitWindow.Std(), keys, trusted, exempt), nil
+}
+
+// Allow conta a requisição do cliente e decide se ela pode prosseguir. Com uma chave aceita,
+// vale o limite da chave; sem ela (ou com uma chave desconhecida), o limite do IP.
+// Se o Store falhar, a requisição é liberada e o erro é devolvido para registro.
+func (l *Limiter) Allow(ctx context.Context, c Client) (Result, error) {
+	key, limit := "ip:"+c.IP, l.perIP
+	if c.APIKey != "" && l.keys[c.APIKey] {
+		// A chave não é guardada em claro no Store
+		sum := sha256.Sum256([]byte(c.APIKey))
+		key, limit = "key:"+hex.EncodeToString(sum[:8]), l.perKey
+	}
+	if limit <= 0 {
+		return Result{Allowed: true}, nil
+	}
+
+	count, reset, err := l.store.Increment(ctx, key, l.window)
+	if err != nil {
+		return Result{Allowed: true}, err
+	}
+	return Result{
+		Allowed:   count <= limit,
+		Limit:     limit,
+		Remaining: max(0, limit-count),
+		Reset:     reset,
+	}, nil
+}
+
+// isExempt indica se a conexão vem de uma origem isenta do limite. Vale o endereço da
+// conexão, e não o X-Forwarded-For, que o próprio cliente pode forjar.
+func (l *Limiter) isExempt(remoteAddr string) bool {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+	return contains(l.exempt, ip)
+}
+
+// clientIP identifica o IP do cliente a partir do endereço da conexão. Se a conexão vier
+// de um proxy confiável, percorre o X-Forwarded-For do último para o primeiro endereço,
+// parando no primeiro que não seja de outro proxy confiável.
+func (l *Limiter) clientIP(remoteAddr, forwardedFor string) string {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+	if forwardedFor == "" || !l.isTrusted(ip) {
+		return ip
+	}
+
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !l.isTrusted(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (l *Limiter) isTrusted(ip string) bool {
+	return contains(l.trusted, ip)
+}
+
+func contains(nets []*net.IPNet, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range nets {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNetworks interpreta uma lista de IPs ou CIDRs separados por vírgula (ex.: "127.0.0.1,10.0.0.0/8")
+func ParseNetworks(list string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			if ip := net.ParseIP(item); ip != nil && ip.To4() != nil {
+				item += "/32"
+			} else {
+				item += "/128"
+			}
+		}
+		_, n, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("endereço de rede inválido: %q", item)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// LoadKeys lê o arquivo de chaves de API aceitas, uma por linha. Só o primeiro campo
+// da linha é usado (os escopos que o seguem são da autenticação, no pacote auth).
+// Linhas vazias e iniciadas por # são ignoradas. Sem arquivo, nenhuma chave é aceita.
+func LoadKeys(path string) (map[string]bool, error) {
+	keys := make(map[string]bool)
+	if path == "" {
+		return keys, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir arquivo de chaves de API: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		keys[fields[0]] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao ler arquivo de chaves de API: %v", err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// mustNetworks interpreta a lista de redes, falhando o teste se ela for inválida
+func mustNetworks(t *testing.T, list string) []*net.IPNet {
+	t.Helper()
+	nets, err := ParseNetworks(list)
+	if err != nil {
+		t.Fatalf("ParseNetworks(%q): %v", list, err)
+	}
+	return nets
+}
+
+func TestParseNetworks(t *testing.T) {
+	tests := []struct {
+		list     string
+		wantErr  bool
+		contains []string
+		excludes []string
+	}{
+		{list: "", contains: nil, excludes: []string{"127.0.0.1"}},
+		{list: "127.0.0.1", contains: []string{"127.0.0.1"}, excludes: []string{"127.0.0.2"}},
+		{list: " 10.0.0.0/8 , ::1", contains: []string{"10.1.2.3", "::1"}, excludes: []string{"11.0.0.1", "::2"}},
+		{list: "2001:db8::/32", contains: []string{"2001:db8::1"}, excludes: []string{"2001:db9::1"}},
+		{list: "127.0.0.1,,", contains: []string{"127.0.0.1"}},
+		{list: "localhost", wantErr: true},
+		{list: "10.0.0.0/33", wantErr: true},
+		{list: "10.0.0.1,300.0.0.1", wantErr: true},
+	}
+	for _, tt := range tests {
+		nets, err := ParseNetworks(tt.list)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseNetworks(%q) erro = %v, quer erro: %v", tt.list, err, tt.wantErr)
+			continue
+		}
+		for _, ip := range tt.contains {
+			if !contains(nets, ip) {
+				t.Errorf("ParseNetworks(%q) não contém %s", tt.list, ip)
+			}
+		}
+		for _, ip := range tt.excludes {
+			if contains(nets, ip) {
+				t.Errorf("ParseNetworks(%q) contém %s", tt.list, ip)
+			}
+		}
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	l := New(NewMemoryStore(), 1, 1, time.Minute, nil, mustNetworks(t, "10.0.0.0/8,::1"), nil)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		want         string
+	}{
+		{"sem proxy", "203.0.113.7:5000", "", "203.0.113.7"},
+		{"X-Forwarded-For de origem não confiável é ignorado", "203.0.113.7:5000", "198.51.100.1", "203.0.113.7"},
+		{"proxy confiável", "10.0.0.1:5000", "198.51.100.1", "198.51.100.1"},
+		{"proxy confiável em IPv6", "[::1]:5000", "198.51.100.1", "198.51.100.1"},
+		{"cadeia de proxies percorrida da direita para a esquerda", "10.0.0.1:5000", "198.51.100.1, 10.0.0.3, 10.0.0.2", "198.51.100.1"},
+		{"endereço forjado à esquerda do cliente real", "10.0.0.1:5000", "192.0.2.66, 198.51.100.1", "198.51.100.1"},
+		{"lixo interrompe a cadeia", "10.0.0.1:5000", "198.51.100.1, lixo, 10.0.0.2", "10.0.0.2"},
+		{"lixo logo após o proxy", "10.0.0.1:5000", "198.51.100.1, unknown", "10.0.0.1"},
+		{"só proxies confiáveis", "10.0.0.1:5000", "10.0.0.3, 10.0.0.2", "10.0.0.3"},
+		{"endereço sem porta", "10.0.0.1", "198.51.100.1", "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.clientIP(tt.remoteAddr, tt.forwardedFor); got != tt.want {
+				t.Errorf("clientIP(%q, %q) = %s, quer %s", tt.remoteAddr, tt.forwardedFor, got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestLimiter cria um limitador com relógio controlado pelo teste
+func newTestLimiter(perIP, perKey int, window time.Duration, keys map[string]bool, exempt []*net.IPNet) (*Limiter, *time.Time) {
+	now := time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	l := New(store, perIP, perKey, window, keys, nil, exempt)
+	l.now = store.now
+	return l, &now
+}
+
+func TestAllowWindowBoundary(t *testing.T) {
+	l, now := newTestLimiter(2, 0, time.Minute, nil, nil)
+	ctx := context.Background()
+	client := Client{IP: "203.0.113.7"}
+
+	// A janela é alinhada ao relógio: começa às 12:00:00 e termina às 12:01:00
+	*now = now.Add(58 * time.Second)
+	for i, want := range []bool{true, true, false} {
+		res, err := l.Allow(ctx, client)
+		if err != nil || res.Allowed != want {
+			t.Fatalf("requisição %d: Allowed = %v (%v), quer %v", i+1, res.Allowed, err, want)
+		}
+	}
+	res, _ := l.Allow(ctx, client)
+	if res.Remaining != 0 || res.RetryAfter(*now) != 2*time.Second {
+		t.Errorf("Remaining = %d, RetryAfter = %s, quer 0 e 2s até o fim da janela", res.Remaining, res.RetryAfter(*now))
+	}
+
+	// Um instante antes do fim ainda vale a janela atual; no limite exato, começa outra
+	*now = now.Add(2*time.Second - time.Nanosecond)
+	if res, _ := l.Allow(ctx, client); res.Allowed {
+		t.Errorf("requisição liberada antes do fim da janela")
+	}
+	*now = now.Add(time.Nanosecond)
+	res, _ = l.Allow(ctx, client)
+	if !res.Allowed || res.Remaining != 1 || !res.Reset.Equal(now.Add(time.Minute)) {
+		t.Errorf("nova janela: Allowed = %v, Remaining = %d, Reset = %s, quer liberada com 1 restante até %s", res.Allowed, res.Remaining, res.Reset, now.Add(time.Minute))
+	}
+
+	// Outro IP tem a sua própria contagem
+	if res, _ := l.Allow(ctx, Client{IP: "203.0.113.8"}); !res.Allowed || res.Remaining != 1 {
+		t.Errorf("outro IP: Allowed = %v, Remaining = %d, quer liberada com 1 restante", res.Allowed, res.Remaining)
+	}
+}
+
+func TestAllowAPIKeys(t *testing.T) {
+	l, _ := newTestLimiter(1, 3, time.Minute, map[string]bool{"chave-boa": true}, nil)
+	ctx := context.Background()
+
+	// A chave aceita tem limite próprio, separado do IP
+	for i := 0; i < 3; i++ {
+		if res, _ := l.Allow(ctx, Client{IP: "203.0.113.7", APIKey: "chave-boa"}); !res.Allowed || res.Limit != 3 {
+			t.Fatalf("requisição %d com chave: Allowed = %v, Limit = %d", i+1, res.Allowed, res.Limit)
+		}
+	}
+	// Uma chave desconhecida conta no limite do IP
+	if res, _ := l.Allow(ctx, Client{IP: "203.0.113.7", APIKey: "chave-falsa"}); !res.Allowed || res.Limit != 1 {
+		t.Errorf("chave desconhecida: Allowed = %v, Limit = %d, quer o limite do IP", res.Allowed, res.Limit)
+	}
+	if res, _ := l.Allow(ctx, Client{IP: "203.0.113.7"}); res.Allowed {
+		t.Errorf("IP liberado depois de esgotar o limite com a chave desconhecida")
+	}
+}
+
+func TestMiddlewareExempt(t *testing.T) {
+	l, _ := newTestLimiter(1, 0, time.Minute, nil, mustNetworks(t, "127.0.0.1"))
+	handler := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), func(w http.ResponseWriter, r *http.Request, res Result) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	do := func(remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/weather", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// A origem isenta nunca é limitada nem recebe os cabeçalhos de limite
+	for i := 0; i < 3; i++ {
+		if rec := do("127.0.0.1:5000", ""); rec.Code != http.StatusNoContent || rec.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("origem isenta: status %d, X-RateLimit-Limit %q", rec.Code, rec.Header().Get("X-RateLimit-Limit"))
+		}
+	}
+
+	// Forjar o X-Forwarded-For com o endereço isento não adianta
+	if rec := do("203.0.113.7:5000", "127.0.0.1"); rec.Code != http.StatusNoContent {
+		t.Fatalf("primeira requisição: status %d", rec.Code)
+	}
+	rec := do("203.0.113.7:5000", "127.0.0.1")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("segunda requisição: status %d, Retry-After %q, quer 429 com Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Variável de ambiente com a chave da API do OpenWeather
+const openWeatherKeyEnv = "WEATHER_OPENWEATHER_API_KEY"
+
+// Intervalo de verificação de mudanças no arquivo de segredo
+const secretReloadInterval = 10 * time.Second
+
+// secretSource fornece o valor atual de um segredo.
+// A chave nunca fica em código ou em arquivos de configuração: vem do ambiente
+// ou de um arquivo montado (ex.: Docker/Kubernetes secrets), que pode ser rotacionado.
+type secretSource interface {
+	Value() string
+}
+
+// staticSecret é um segredo fixo, lido uma única vez (ex.: variável de ambiente)
+type staticSecret string
+
+func (s staticSecret) Value() string {
+	return string(s)
+}
+
+// fileSecret lê o segredo de um arquivo e o recarrega quando o conteúdo muda
+type fileSecret struct {
+	path string
+
+	mu    sync.RWMutex
+	value string
+}
+
+func newFileSecret(path string) (*fileSecret, error) {
+	s := &fileSecret{path: path}
+	value, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.value = value
+	return s, nil
+}
+
+func (s *fileSecret) Value() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.value
+}
+
+// read lê o arquivo e remove espaços e quebras de linha nas extremidades
+func (s *fileSecret) read() (string, error) {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("falha ao ler arquivo de segredo: %v", err)
+	}
+	value := string(bytes.TrimSpace(data))
+	if value == "" {
+		return "", fmt.Errorf("arquivo de segredo %s está vazio", s.path)
+	}
+	return value, nil
+}
+
+// watch verifica o arquivo periodicamente até o contexto ser cancelado.
+// A comparação é feita pelo conteúdo, pois secrets montados costumam ser trocados
+// por links simbólicos sem alterar a data de modificação do caminho original.
+// Se a leitura falhar, o valor anterior continua em uso.
+func (s *fileSecret) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		value, err := s.read()
+		if err != nil {
+			log.Printf("Mantendo segredo anterior: %v", err)
+			continue
+		}
+
+		s.mu.Lock()
+		changed := value != s.value
+		s.value = value
+		s.mu.Unlock()
+
+		if changed {
+			log.Printf("Segredo recarregado de %s", s.path)
+		}
+	}
+}
+
+// loadOpenWeatherKey obtém a chave da API do OpenWeather do arquivo configurado
+// ou, na falta dele, da variável de ambiente. Um arquivo é acompanhado para recarga.
+func loadOpenWeatherKey(ctx context.Context, keyFile string) (secretSource, error) {
+	if keyFile != "" {
+		s, err := newFileSecret(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		go s.watch(ctx, secretReloadInterval)
+		return s, nil
+	}
+
+	if key := strings.TrimSpace(os.Getenv(openWeatherKeyEnv)); key != "" {
+		return staticSecret(key), nil
+	}
+
+	return nil, fmt.Errorf("chave da API do OpenWeather não configurada: defina a variável %s "+
+		"ou informe um arquivo com -openweather-key-file", openWeatherKeyEnv)
+}
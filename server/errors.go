@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Corpo JSON enviado ao cliente em caso de erro (ex.: {"error": {"status": 404, ...}})
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// Estrutura com os dados do erro, incluindo os detalhes enviados pelo servidor gRPC
+type ErrorBody struct {
+	Status     int              `json:"status"`
+	Code       string           `json:"code"`
+	Message    string           `json:"message"`
+	Reason     string           `json:"reason,omitempty"`
+	Fields     []FieldViolation `json:"fields,omitempty"`
+	RetryAfter int64            `json:"retryAfter,omitempty"` // Segundos até uma nova tentativa
+}
+
+// Campo inválido da requisição
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Tradução dos códigos gRPC para os status HTTP correspondentes
+var httpStatusByCode = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Canceled:           499, // Cliente fechou a requisição
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// httpStatusFromCode retorna o status HTTP de um código gRPC (500 para os demais)
+func httpStatusFromCode(code codes.Code) int {
+	if s, ok := httpStatusByCode[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// errorBodyFromStatus monta o corpo de erro a partir do status gRPC e seus detalhes
+func errorBodyFromStatus(err error) ErrorBody {
+	st := status.Convert(err)
+	body := ErrorBody{
+		Status:  httpStatusFromCode(st.Code()),
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	}
+
+	for _, d := range st.Details() {
+		switch detail := d.(type) {
+		case *errdetails.ErrorInfo:
+			body.Reason = detail.Reason
+		case *errdetails.BadRequest:
+			for _, v := range detail.FieldViolations {
+				body.Fields = append(body.Fields, FieldViolation{Field: v.Field, Description: v.Description})
+			}
+		case *errdetails.RetryInfo:
+			body.RetryAfter = retryAfterSeconds(detail.RetryDelay.AsDuration())
+		}
+	}
+	return body
+}
+
+// retryAfterSeconds converte a espera sugerida em segundos inteiros para o Retry-After,
+// arredondando para cima: uma espera menor que um segundo vira 1, e não 0 (sem cabeçalho)
+func retryAfterSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// writeGRPCError responde com o status HTTP e o corpo JSON equivalentes ao erro gRPC
+func writeGRPCError(w http.ResponseWriter, err error) {
+	writeErrorBody(w, errorBodyFromStatus(err))
+}
+
+// writeError responde com um erro gerado pelo próprio gateway (ex.: parâmetro ausente)
+func writeError(w http.ResponseWriter, httpStatus int, code codes.Code, message string) {
+	writeErrorBody(w, ErrorBody{Status: httpStatus, Code: code.String(), Message: message})
+}
+
+func writeErrorBody(w http.ResponseWriter, body ErrorBody) {
+	if body.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(body.RetryAfter, 10))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.Status)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: body})
+}
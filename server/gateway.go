@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"grpc-client/auth"
+	"grpc-client/config"
+	"grpc-client/ratelimit"
+	pb "grpc-client/web"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // Habilita o health check do lado do cliente
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+)
+
+// Nome do serviço consultado no health check do servidor gRPC
+const weatherServiceName = "web.WeatherService"
+
+// Configuração de serviço do cliente: só envia chamadas ao servidor que se declara saudável
+const serviceConfig = `{"healthCheckConfig": {"serviceName": "` + weatherServiceName + `"}}`
+
+// gateway concentra a conexão gRPC usada por todos os handlers HTTP.
+// Uma única *grpc.ClientConn de longa duração é compartilhada entre as requisições,
+// aproveitando a multiplexação do HTTP/2 em vez de conectar a cada chamada.
+type gateway struct {
+	cfg     *config.GatewayConfig
+	conn    *grpc.ClientConn
+	client  pb.WeatherServiceClient
+	health  healthpb.HealthClient
+	limiter *ratelimit.Limiter
+	auth    *auth.Authenticator // nil quando o gateway não exige token
+}
+
+// newGateway cria a conexão gerenciada com o servidor gRPC no endereço configurado.
+// A conexão mantém keepalive, reconecta com backoff exponencial e é iniciada
+// imediatamente para que a primeira requisição não pague o custo da conexão.
+// As chamadas levam o IP, a chave de API e o token do cliente final, para o limite
+// e a autenticação do servidor gRPC.
+func newGateway(cfg *config.GatewayConfig) (*gateway, error) {
+	limiter, err := ratelimit.NewFromConfig(cfg.RateLimitConfig, ratelimit.NewMemoryStore())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar limite de requisições: %v", err)
+	}
+	var authenticator *auth.Authenticator
+	if cfg.AuthRequired {
+		if authenticator, err = auth.NewFromConfig(cfg.AuthConfig, cfg.APIKeysFile); err != nil {
+			return nil, fmt.Errorf("erro ao configurar autenticação: %v", err)
+		}
+	}
+
+	conn, err := grpc.NewClient(cfg.GRPCTarget,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                30 * time.Second, // Intervalo entre pings quando a conexão está ociosa
+			Timeout:             10 * time.Second, // Tempo máximo de espera pela resposta do ping
+			PermitWithoutStream: true,
+		}),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff: backoff.Config{
+				BaseDelay:  500 * time.Millisecond,
+				Multiplier: 1.6,
+				Jitter:     0.2,
+				MaxDelay:   15 * time.Second,
+			},
+			MinConnectTimeout: 5 * time.Second,
+		}),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(ratelimit.UnaryClientInterceptor(), auth.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(ratelimit.StreamClientInterceptor(), auth.StreamClientInterceptor()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao configurar conexão gRPC: %v", err)
+	}
+	conn.Connect()
+
+	return &gateway{
+		cfg:     cfg,
+		conn:    conn,
+		client:  pb.NewWeatherServiceClient(conn),
+		health:  healthpb.NewHealthClient(conn),
+		limiter: limiter,
+		auth:    authenticator,
+	}, nil
+}
+
+// api protege uma rota da API: limite de requisições por cliente e, se exigido, token com o escopo da rota
+func (g *gateway) api(scope string, h http.HandlerFunc) http.Handler {
+	return g.limited(g.authorized(scope, h))
+}
+
+// authorized exige um token com o escopo informado. Sem autenticação no gateway,
+// o token recebido (se houver) é apenas repassado ao servidor gRPC.
+func (g *gateway) authorized(scope string, h http.HandlerFunc) http.Handler {
+	if g.auth == nil {
+		return auth.Forward(h)
+	}
+	return g.auth.Middleware(scope, h, func(w http.ResponseWriter, r *http.Request, status int, message string) {
+		code := codes.Unauthenticated
+		if status == http.StatusForbidden {
+			code = codes.PermissionDenied
+		}
+		writeError(w, status, code, message)
+	})
+}
+
+// limited aplica o limite de requisições por cliente a uma rota da API
+func (g *gateway) limited(h http.Handler) http.Handler {
+	return g.limiter.Middleware(h, func(w http.ResponseWriter, r *http.Request, res ratelimit.Result) {
+		writeErrorBody(w, ErrorBody{
+			Status:     http.StatusTooManyRequests,
+			Code:       codes.ResourceExhausted.String(),
+			Message:    "Limite de requisições atingido, tente novamente mais tarde",
+			Reason:     "RATE_LIMITED",
+			RetryAfter: int64(res.RetryAfter(time.Now()) / time.Second),
+		})
+	})
+}
+
+// Close encerra a conexão gRPC
+func (g *gateway) Close() error {
+	return g.conn.Close()
+}
+
+// Função para lidar com a rota /healthz
+// Indica apenas que o processo do gateway está no ar (liveness).
+func (g *gateway) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok"))
+}
+
+// Função para lidar com a rota /readyz
+// O gateway só está pronto se a conexão gRPC estiver ativa e o servidor
+// responder ao health check como SERVING.
+func (g *gateway) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if err := g.ready(r.Context()); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte("ok"))
+}
+
+// ready verifica o estado da conexão e consulta o health check do servidor gRPC
+func (g *gateway) ready(ctx context.Context) error {
+	if state := g.conn.GetState(); state == connectivity.TransientFailure || state == connectivity.Shutdown {
+		g.conn.Connect()
+		return fmt.Errorf("conexão gRPC indisponível: %s", state)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	res, err := g.health.Check(ctx, &healthpb.HealthCheckRequest{Service: weatherServiceName})
+	if err != nil {
+		return fmt.Errorf("health check falhou: %v", err)
+	}
+	if res.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("servidor gRPC não está pronto: %s", res.Status)
+	}
+	return nil
+}
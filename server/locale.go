@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"golang.org/x/text/language"
+)
+
+// requestLocale determina o idioma das descrições pedido pelo cliente. O parâmetro ?lang=
+// tem precedência sobre o cabeçalho Accept-Language, do qual é usado o idioma preferido.
+// A tag é repassada ao servidor gRPC, que a aproxima dos idiomas suportados.
+func requestLocale(r *http.Request) (string, error) {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return "", fmt.Errorf("Parâmetro lang inválido: use uma tag BCP 47 como pt-BR ou en")
+		}
+		return tag.String(), nil
+	}
+
+	// Um Accept-Language malformado é ignorado, e o servidor gRPC usa o idioma padrão
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 || tags[0] == language.Und {
+		return "", nil
+	}
+	return tags[0].String(), nil
+}
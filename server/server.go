@@ -3,90 +3,824 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"grpc-client/auth"
+	"grpc-client/config"
 	pb "grpc-client/web" // Ajuste o caminho para o pacote gerado
 
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 // Estrutura para armazenar a resposta do clima que será enviada ao cliente
+// City, Country, CityID e Coordinates identificam a cidade resolvida pelo servidor.
 type WeatherResponse struct {
-	City        string  `json:"city"`
-	Description string  `json:"description"`
-	Temperature float32 `json:"temperature"`
+	City        string       `json:"city"`
+	Country     string       `json:"country,omitempty"`
+	CityID      int64        `json:"cityId,omitempty"`
+	Coordinates *Coordinates `json:"coordinates,omitempty"`
+	Description string       `json:"description"`
+	Temperature float32      `json:"temperature"`
+	Units       string       `json:"units"`
+
+	// Detalhes da observação, nas mesmas unidades de Temperature
+	FeelsLike     float32 `json:"feelsLike"`
+	TempMin       float32 `json:"tempMin"`
+	TempMax       float32 `json:"tempMax"`
+	Humidity      int32   `json:"humidity"`
+	Pressure      int32   `json:"pressure"`
+	WindSpeed     float32 `json:"windSpeed"`
+	WindDirection int32   `json:"windDirection"`
+	CloudCover    int32   `json:"cloudCover"`
+	Visibility    int32   `json:"visibility"`
+	// Nascer e pôr do sol em RFC 3339, no fuso horário da cidade
+	Sunrise       string `json:"sunrise,omitempty"`
+	Sunset        string `json:"sunset,omitempty"`
+	ConditionCode int32  `json:"conditionCode"`
+	Icon          string `json:"icon,omitempty"`
+
+	// Procedência dos dados: horário da observação e da consulta ao fornecedor (RFC 3339, UTC),
+	// fornecedor de origem e se a resposta veio do cache do servidor
+	ObservedAt string `json:"observedAt,omitempty"`
+	FetchedAt  string `json:"fetchedAt,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Cached     bool   `json:"cached"`
+
+	// Indica que o fornecedor falhou e a resposta é a última observação conhecida,
+	// com a idade dela em segundos
+	Stale bool  `json:"stale"`
+	Age   int64 `json:"age,omitempty"`
 }
 
-// Função para buscar os dados de clima via gRPC
-func getWeatherData(city string) (*WeatherResponse, error) {
-	// Conecta ao servidor gRPC
-	conn, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
-	if err != nil {
-		return nil, fmt.Errorf("erro ao conectar ao servidor gRPC: %v", err)
+// Coordenadas geográficas da cidade resolvida, em graus decimais
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Converte a resposta gRPC para o formato JSON enviado ao cliente
+func weatherFromProto(res *pb.WeatherResponse) *WeatherResponse {
+	return &WeatherResponse{
+		City:        res.City,
+		Country:     res.Country,
+		CityID:      res.CityId,
+		Coordinates: coordinatesFromProto(res.Coordinates),
+		Description: res.Description,
+		Temperature: res.Temperature,
+		Units:       unitsName(res.Units),
+
+		FeelsLike:     res.FeelsLike,
+		TempMin:       res.TempMin,
+		TempMax:       res.TempMax,
+		Humidity:      res.Humidity,
+		Pressure:      res.Pressure,
+		WindSpeed:     res.WindSpeed,
+		WindDirection: res.WindDirection,
+		CloudCover:    res.CloudCover,
+		Visibility:    res.Visibility,
+		Sunrise:       localTime(res.Sunrise, res.TimezoneOffset),
+		Sunset:        localTime(res.Sunset, res.TimezoneOffset),
+		ConditionCode: res.ConditionCode,
+		Icon:          res.Icon,
+
+		ObservedAt: localTime(res.ObservedAt, 0),
+		FetchedAt:  localTime(res.FetchedAt, 0),
+		Source:     res.Source,
+		Cached:     res.Cached,
+
+		Stale: res.Stale,
+		Age:   res.Age,
 	}
-	defer conn.Close()
+}
+
+// localTime formata um horário Unix em RFC 3339 com o deslocamento de fuso informado em segundos
+// (ex.: "2024-09-14T06:03:05-03:00"; deslocamento zero resulta em UTC, "...Z").
+// Zero indica horário desconhecido e resulta em texto vazio.
+func localTime(unix int64, offset int32) string {
+	if unix == 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).In(time.FixedZone("", int(offset))).Format(time.RFC3339)
+}
+
+func coordinatesFromProto(c *pb.Coordinates) *Coordinates {
+	if c == nil {
+		return nil
+	}
+	return &Coordinates{Lat: c.Lat, Lon: c.Lon}
+}
 
-	client := pb.NewWeatherServiceClient(conn)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+// Nomes aceitos no parâmetro ?units= (os mesmos usados pelo OpenWeather)
+var unitsByName = map[string]pb.Units{
+	"metric":   pb.Units_UNITS_METRIC,
+	"imperial": pb.Units_UNITS_IMPERIAL,
+	"standard": pb.Units_UNITS_STANDARD,
+	"kelvin":   pb.Units_UNITS_STANDARD,
+}
+
+// parseUnits lê o parâmetro ?units=; vazio deixa a escolha para o servidor gRPC
+func parseUnits(name string) (pb.Units, error) {
+	if name == "" {
+		return pb.Units_UNITS_UNSPECIFIED, nil
+	}
+	u, ok := unitsByName[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("Parâmetro units inválido: use metric, imperial ou standard")
+	}
+	return u, nil
+}
+
+// unitsName retorna o nome da unidade usado no JSON enviado ao cliente
+func unitsName(u pb.Units) string {
+	switch u {
+	case pb.Units_UNITS_IMPERIAL:
+		return "imperial"
+	case pb.Units_UNITS_STANDARD:
+		return "standard"
+	default:
+		return "metric"
+	}
+}
+
+// weatherQuery reúne o que a query string pede ao servidor gRPC: o local, em uma das formas
+// aceitas (?city= com ?country= opcional, ?lat=&lon= ou ?id= com o ID da cidade no fornecedor),
+// a unidade (?units=) e o idioma das descrições (?lang= ou o cabeçalho Accept-Language)
+type weatherQuery struct {
+	city        string
+	country     string
+	cityID      int64
+	coordinates *pb.Coordinates
+	units       pb.Units
+	locale      string
+}
+
+// weatherQueryFromRequest lê a consulta da requisição (ex: ?lat=-23.55&lon=-46.63&units=imperial&lang=en)
+func weatherQueryFromRequest(r *http.Request) (*weatherQuery, error) {
+	query := r.URL.Query()
+	q := &weatherQuery{city: query.Get("city"), country: query.Get("country")}
+
+	kinds := 0
+	if q.city != "" {
+		kinds++
+	}
+	if lat, lon := query.Get("lat"), query.Get("lon"); lat != "" || lon != "" {
+		kinds++
+		latValue, latErr := strconv.ParseFloat(lat, 64)
+		lonValue, lonErr := strconv.ParseFloat(lon, 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("Parâmetros lat e lon inválidos: informe os dois em graus decimais")
+		}
+		q.coordinates = &pb.Coordinates{Lat: latValue, Lon: lonValue}
+	}
+	if id := query.Get("id"); id != "" {
+		kinds++
+		n, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("Parâmetro id inválido")
+		}
+		q.cityID = n
+	}
+
+	switch {
+	case kinds == 0:
+		return nil, fmt.Errorf("Cidade não especificada: use city, lat e lon ou id")
+	case kinds > 1:
+		return nil, fmt.Errorf("Informe apenas uma localização: city, lat e lon ou id")
+	case q.country != "" && q.city == "":
+		return nil, fmt.Errorf("O parâmetro country só pode ser usado com city")
+	}
+
+	var err error
+	if q.units, err = parseUnits(query.Get("units")); err != nil {
+		return nil, err
+	}
+	if q.locale, err = requestLocale(r); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// String descreve o local pedido, para logs
+func (q *weatherQuery) String() string {
+	switch {
+	case q.cityID != 0:
+		return "id " + strconv.FormatInt(q.cityID, 10)
+	case q.coordinates != nil:
+		return fmt.Sprintf("%g,%g", q.coordinates.Lat, q.coordinates.Lon)
+	case q.country != "":
+		return q.city + "," + q.country
+	default:
+		return q.city
+	}
+}
+
+// weatherRequest monta a requisição gRPC de clima atual
+func (q *weatherQuery) weatherRequest() *pb.WeatherRequest {
+	req := &pb.WeatherRequest{Units: q.units, Locale: q.locale}
+	switch {
+	case q.cityID != 0:
+		req.Location = &pb.WeatherRequest_CityId{CityId: q.cityID}
+	case q.coordinates != nil:
+		req.Location = &pb.WeatherRequest_Coordinates{Coordinates: q.coordinates}
+	case q.country != "":
+		req.Location = &pb.WeatherRequest_CityCountry{CityCountry: &pb.CityCountry{City: q.city, Country: q.country}}
+	default:
+		req.Location = &pb.WeatherRequest_City{City: q.city}
+	}
+	return req
+}
+
+// forecastRequest monta a requisição gRPC de previsão para o horizonte informado
+func (q *weatherQuery) forecastRequest(days int32) *pb.ForecastRequest {
+	req := &pb.ForecastRequest{Units: q.units, Locale: q.locale, Days: days}
+	switch {
+	case q.cityID != 0:
+		req.Location = &pb.ForecastRequest_CityId{CityId: q.cityID}
+	case q.coordinates != nil:
+		req.Location = &pb.ForecastRequest_Coordinates{Coordinates: q.coordinates}
+	case q.country != "":
+		req.Location = &pb.ForecastRequest_CityCountry{CityCountry: &pb.CityCountry{City: q.city, Country: q.country}}
+	default:
+		req.Location = &pb.ForecastRequest_City{City: q.city}
+	}
+	return req
+}
+
+// Função para buscar os dados de clima via gRPC
+func (g *gateway) getWeatherData(ctx context.Context, req *pb.WeatherRequest) (*WeatherResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.Timeout.Std())
 	defer cancel()
 
 	// Faz a requisição gRPC para obter os dados de clima
-	res, err := client.GetWeather(ctx, &pb.WeatherRequest{City: city})
+	res, err := g.client.GetWeather(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao obter dados de clima: %v", err)
+		return nil, err
 	}
 
 	// Prepara a resposta com os dados de clima
-	return &WeatherResponse{
+	return weatherFromProto(res), nil
+}
+
+// Resultado de uma cidade na busca em lote: Weather em caso de sucesso, Error caso contrário
+type WeatherBatchResult struct {
+	City    string           `json:"city"`
+	Weather *WeatherResponse `json:"weather,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// Estrutura da resposta da rota /weather/batch
+type WeatherBatchResponse struct {
+	Results []WeatherBatchResult `json:"results"`
+}
+
+// Corpo aceito no POST da rota /weather/batch
+// Sem "locale", o idioma vem do cabeçalho Accept-Language.
+type WeatherBatchRequest struct {
+	Cities []string `json:"cities"`
+	Units  string   `json:"units"`
+	Locale string   `json:"locale"`
+}
+
+// Função para buscar o clima de várias cidades via gRPC em uma única chamada
+func (g *gateway) getWeatherBatchData(ctx context.Context, req *pb.WeatherBatchRequest) (*WeatherBatchResponse, error) {
+	// Um lote grande leva mais tempo que uma cidade isolada
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.BatchTimeout.Std())
+	defer cancel()
+
+	res, err := g.client.GetWeatherBatch(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &WeatherBatchResponse{Results: make([]WeatherBatchResult, 0, len(res.Results))}
+	for _, r := range res.Results {
+		result := WeatherBatchResult{City: r.City, Error: r.Error}
+		if r.Weather != nil {
+			result.Weather = weatherFromProto(r.Weather)
+		}
+		batch.Results = append(batch.Results, result)
+	}
+	return batch, nil
+}
+
+// Estrutura de uma entrada de previsão enviada ao cliente
+type ForecastEntry struct {
+	Time                     string  `json:"time"`
+	Temperature              float32 `json:"temperature"`
+	TempMin                  float32 `json:"tempMin"`
+	TempMax                  float32 `json:"tempMax"`
+	PrecipitationProbability float32 `json:"precipitationProbability"`
+	WindSpeed                float32 `json:"windSpeed"`
+	Humidity                 int32   `json:"humidity"`
+	ConditionCode            int32   `json:"conditionCode"`
+	Description              string  `json:"description"`
+}
+
+// Estrutura para armazenar a previsão que será enviada ao cliente
+type ForecastResponse struct {
+	City        string          `json:"city"`
+	Country     string          `json:"country,omitempty"`
+	CityID      int64           `json:"cityId,omitempty"`
+	Coordinates *Coordinates    `json:"coordinates,omitempty"`
+	Units       string          `json:"units"`
+	Hourly      []ForecastEntry `json:"hourly"`
+	Daily       []ForecastEntry `json:"daily"`
+	Source      string          `json:"source,omitempty"`
+}
+
+// Função para buscar a previsão do tempo via gRPC
+func (g *gateway) getForecastData(ctx context.Context, req *pb.ForecastRequest) (*ForecastResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.Timeout.Std())
+	defer cancel()
+
+	// Faz a requisição gRPC para obter a previsão
+	res, err := g.client.GetForecast(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ForecastResponse{
 		City:        res.City,
-		Description: res.Description,
-		Temperature: res.Temperature,
+		Country:     res.Country,
+		CityID:      res.CityId,
+		Coordinates: coordinatesFromProto(res.Coordinates),
+		Units:       unitsName(res.Units),
+		Hourly:      forecastEntriesFromProto(res.Hourly),
+		Daily:       forecastEntriesFromProto(res.Daily),
+		Source:      res.Source,
 	}, nil
 }
 
+// Converte as entradas de previsão gRPC para JSON, com horários em RFC 3339
+func forecastEntriesFromProto(entries []*pb.ForecastEntry) []ForecastEntry {
+	out := make([]ForecastEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, ForecastEntry{
+			Time:                     time.Unix(e.Time, 0).UTC().Format(time.RFC3339),
+			Temperature:              e.Temperature,
+			TempMin:                  e.TempMin,
+			TempMax:                  e.TempMax,
+			PrecipitationProbability: e.PrecipitationProbability,
+			WindSpeed:                e.WindSpeed,
+			Humidity:                 e.Humidity,
+			ConditionCode:            e.ConditionCode,
+			Description:              e.Description,
+		})
+	}
+	return out
+}
+
 // Função para lidar com a rota /weather e buscar o clima via gRPC
-func handleWeather(w http.ResponseWriter, r *http.Request) {
-	// Obtém a cidade da query string (ex: ?city=SaoPaulo)
-	city := r.URL.Query().Get("city")
-	if city == "" {
-		http.Error(w, "Cidade não especificada", http.StatusBadRequest)
+func (g *gateway) handleWeather(w http.ResponseWriter, r *http.Request) {
+	// Obtém o local, a unidade e o idioma da requisição (ex: ?city=SaoPaulo&units=metric ou ?lat=-23.55&lon=-46.63)
+	query, err := weatherQueryFromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
 		return
 	}
 
 	// Faz a chamada ao gRPC para buscar os dados do clima
-	weatherData, err := getWeatherData(city)
+	weatherData, err := g.getWeatherData(r.Context(), query.weatherRequest())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeGRPCError(w, err)
 		return
 	}
 
-	// Define o cabeçalho da resposta como JSON
+	// Define o cabeçalho da resposta como JSON; o conteúdo varia com o idioma pedido
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	if weatherData.Stale {
+		// Aviso padrão do HTTP para respostas desatualizadas (RFC 7234, seção 5.5.1)
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
 	// Envia a resposta JSON para o frontend
 	json.NewEncoder(w).Encode(weatherData)
 }
 
+// Função para lidar com a rota /forecast (ex: ?city=SaoPaulo&days=3)
+// Aceita as mesmas formas de localização da rota /weather.
+func (g *gateway) handleForecast(w http.ResponseWriter, r *http.Request) {
+	query, err := weatherQueryFromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	// O horizonte é opcional; sem ele o servidor gRPC usa o padrão
+	var days int32
+	if d := r.URL.Query().Get("days"); d != "" {
+		n, err := strconv.ParseInt(d, 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codes.InvalidArgument, "Parâmetro days inválido")
+			return
+		}
+		days = int32(n)
+	}
+
+	forecast, err := g.getForecastData(r.Context(), query.forecastRequest(days))
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(forecast)
+}
+
+// Ponto da série histórica enviado ao cliente
+type HistoryPoint struct {
+	Time          string  `json:"time"`
+	Temperature   float32 `json:"temperature"`
+	TempMin       float32 `json:"tempMin"`
+	TempMax       float32 `json:"tempMax"`
+	Humidity      int32   `json:"humidity"`
+	Pressure      int32   `json:"pressure"`
+	WindSpeed     float32 `json:"windSpeed"`
+	ConditionCode int32   `json:"conditionCode"`
+	Description   string  `json:"description"`
+	Samples       int32   `json:"samples"`
+}
+
+// Estrutura da resposta da rota /weather/history
+type HistoryResponse struct {
+	City        string         `json:"city"`
+	Country     string         `json:"country,omitempty"`
+	Units       string         `json:"units"`
+	Granularity string         `json:"granularity"`
+	Points      []HistoryPoint `json:"points"`
+}
+
+// Nomes aceitos no parâmetro ?granularity=
+var granularityByName = map[string]pb.Granularity{
+	"raw":    pb.Granularity_GRANULARITY_RAW,
+	"hourly": pb.Granularity_GRANULARITY_HOURLY,
+	"daily":  pb.Granularity_GRANULARITY_DAILY,
+}
+
+// granularityName retorna o nome da granularidade usado no JSON enviado ao cliente
+func granularityName(g pb.Granularity) string {
+	for name, value := range granularityByName {
+		if value == g {
+			return name
+		}
+	}
+	return "raw"
+}
+
+// parseHistoryTime lê os parâmetros ?from= e ?to=, em RFC 3339 (ex.: 2024-09-14T06:00:00-03:00)
+// ou apenas a data em UTC (ex.: 2024-09-14). Vazio deixa a escolha para o servidor gRPC.
+// O fim do intervalo é exclusivo: uma data sem horário em "to" vira a meia-noite do dia
+// seguinte, para que o próprio dia entre no histórico (to=2024-09-14 inclui o dia 14).
+func parseHistoryTime(name, value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.Unix(), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		if name == "to" {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t.Unix(), nil
+	}
+	return 0, fmt.Errorf("Parâmetro %s inválido: use RFC 3339 (2024-09-14T06:00:00Z) ou a data (2024-09-14)", name)
+}
+
+// Função para buscar o histórico de observações via gRPC
+func (g *gateway) getHistoryData(ctx context.Context, req *pb.HistoryRequest) (*HistoryResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.Timeout.Std())
+	defer cancel()
+
+	res, err := g.client.GetHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &HistoryResponse{
+		City:        res.City,
+		Country:     res.Country,
+		Units:       unitsName(res.Units),
+		Granularity: granularityName(res.Granularity),
+		Points:      make([]HistoryPoint, 0, len(res.Points)),
+	}
+	for _, p := range res.Points {
+		history.Points = append(history.Points, HistoryPoint{
+			Time:          time.Unix(p.Time, 0).UTC().Format(time.RFC3339),
+			Temperature:   p.Temperature,
+			TempMin:       p.TempMin,
+			TempMax:       p.TempMax,
+			Humidity:      p.Humidity,
+			Pressure:      p.Pressure,
+			WindSpeed:     p.WindSpeed,
+			ConditionCode: p.ConditionCode,
+			Description:   p.Description,
+			Samples:       p.Samples,
+		})
+	}
+	return history, nil
+}
+
+// Função para lidar com a rota /weather/history (ex: ?city=Recife&from=2024-09-01&to=2024-09-08&granularity=daily)
+// Retorna as observações que o servidor registrou para a cidade, usadas no gráfico do frontend.
+func (g *gateway) handleWeatherHistory(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &pb.HistoryRequest{City: query.Get("city"), Country: query.Get("country")}
+	if strings.TrimSpace(req.City) == "" {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, "Cidade não especificada")
+		return
+	}
+
+	var err error
+	if req.From, err = parseHistoryTime("from", query.Get("from")); err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+	if req.To, err = parseHistoryTime("to", query.Get("to")); err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+	if name := query.Get("granularity"); name != "" {
+		granularity, ok := granularityByName[strings.ToLower(name)]
+		if !ok {
+			writeError(w, http.StatusBadRequest, codes.InvalidArgument, "Parâmetro granularity inválido: use raw, hourly ou daily")
+			return
+		}
+		req.Granularity = granularity
+	}
+	if req.Units, err = parseUnits(query.Get("units")); err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	history, err := g.getHistoryData(r.Context(), req)
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// Cidade sugerida pela rota /cities
+type CityResponse struct {
+	Name        string       `json:"name"`
+	Country     string       `json:"country"`
+	Region      string       `json:"region,omitempty"`
+	Coordinates *Coordinates `json:"coordinates"`
+}
+
+// Estrutura da resposta da rota /cities
+type CitiesResponse struct {
+	Cities []CityResponse `json:"cities"`
+}
+
+// Função para buscar cidades pelo nome via gRPC
+func (g *gateway) searchCitiesData(ctx context.Context, req *pb.SearchCitiesRequest) (*CitiesResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.cfg.Timeout.Std())
+	defer cancel()
+
+	res, err := g.client.SearchCities(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	cities := &CitiesResponse{Cities: make([]CityResponse, 0, len(res.Cities))}
+	for _, c := range res.Cities {
+		cities.Cities = append(cities.Cities, CityResponse{
+			Name:        c.Name,
+			Country:     c.Country,
+			Region:      c.Region,
+			Coordinates: coordinatesFromProto(c.Coordinates),
+		})
+	}
+	return cities, nil
+}
+
+// Função para lidar com a rota /cities (ex: ?q=sao pa&limit=5)
+// Usada pelo autocompletar do campo de cidade no frontend.
+func (g *gateway) handleCities(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, "Parâmetro q não especificado")
+		return
+	}
+
+	// O limite é opcional; sem ele o servidor gRPC usa o padrão
+	var limit int32
+	if l := r.URL.Query().Get("limit"); l != "" {
+		n, err := strconv.ParseInt(l, 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, codes.InvalidArgument, "Parâmetro limit inválido")
+			return
+		}
+		limit = int32(n)
+	}
+
+	cities, err := g.searchCitiesData(r.Context(), &pb.SearchCitiesRequest{Query: q, Limit: limit})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cities)
+}
+
+// Tamanho máximo do corpo do POST em /weather/batch; o lote tem no máximo 100 cidades,
+// então 64 KiB sobram para nomes longos
+const maxBatchBodyBytes = 64 << 10
+
+// Função para lidar com a rota /weather/batch
+// Aceita GET com cidades repetidas (ex: ?city=SaoPaulo&city=Recife) ou
+// POST com um JSON no formato {"cities": ["SaoPaulo", "Recife"]}.
+func (g *gateway) handleWeatherBatch(w http.ResponseWriter, r *http.Request) {
+	var cities []string
+	var unitsParam, locale string
+	switch r.Method {
+	case http.MethodGet:
+		cities = r.URL.Query()["city"]
+		unitsParam = r.URL.Query().Get("units")
+	case http.MethodPost:
+		var req WeatherBatchRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodyBytes)).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, http.StatusRequestEntityTooLarge, codes.ResourceExhausted,
+					fmt.Sprintf("Corpo da requisição excede o limite de %d bytes", tooLarge.Limit))
+				return
+			}
+			writeError(w, http.StatusBadRequest, codes.InvalidArgument, "JSON inválido")
+			return
+		}
+		cities = req.Cities
+		unitsParam = req.Units
+		locale = req.Locale
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, codes.Unimplemented, "Método não permitido")
+		return
+	}
+
+	if len(cities) == 0 {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, "Nenhuma cidade especificada")
+		return
+	}
+
+	units, err := parseUnits(unitsParam)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	if locale == "" {
+		if locale, err = requestLocale(r); err != nil {
+			writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+			return
+		}
+	}
+
+	batch, err := g.getWeatherBatchData(r.Context(), &pb.WeatherBatchRequest{Cities: cities, Units: units, Locale: locale})
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(batch)
+}
+
+// Função para lidar com a rota /weather/stream (ex: ?city=SaoPaulo&units=imperial)
+// Abre uma assinatura gRPC (SubscribeWeather) e repassa cada atualização ao navegador
+// como Server-Sent Events, até o cliente fechar a conexão.
+func (g *gateway) handleWeatherStream(w http.ResponseWriter, r *http.Request) {
+	query, err := weatherQueryFromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codes.InvalidArgument, err.Error())
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, codes.Internal, "Streaming não suportado")
+		return
+	}
+
+	// A assinatura dura enquanto a requisição HTTP estiver aberta
+	stream, err := g.client.SubscribeWeather(r.Context(), query.weatherRequest())
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	// Erros da assinatura (ex.: cidade inexistente) chegam na primeira mensagem.
+	// Aguardá-la antes de abrir o stream permite responder com o status HTTP correto.
+	res, err := stream.Recv()
+	if err != nil {
+		writeGRPCError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Vary", "Accept-Language")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for {
+		data, err := json.Marshal(weatherFromProto(res))
+		if err != nil {
+			log.Printf("Erro ao codificar evento de clima: %v", err)
+			return
+		}
+
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+
+		res, err = stream.Recv()
+		if err != nil {
+			if r.Context().Err() == nil {
+				log.Printf("Assinatura de clima encerrada para %s: %v", query, err)
+				writeStreamError(w, err)
+				flusher.Flush()
+			}
+			return
+		}
+	}
+}
+
+// writeStreamError envia o erro ao navegador como um evento "weather-error",
+// já que o status HTTP não pode mais ser alterado depois de o stream começar.
+func writeStreamError(w http.ResponseWriter, err error) {
+	data, _ := json.Marshal(ErrorResponse{Error: errorBodyFromStatus(err)})
+	fmt.Fprintf(w, "event: weather-error\ndata: %s\n\n", data)
+}
+
 // Função para servir o arquivo index.html
-func serveIndex(w http.ResponseWriter, r *http.Request) {
-	// Serve o arquivo index.html da pasta frontend
-	http.ServeFile(w, r, "frontend/index.html")
+func (g *gateway) serveIndex(w http.ResponseWriter, r *http.Request) {
+	// Serve o arquivo index.html da pasta do frontend
+	http.ServeFile(w, r, filepath.Join(g.cfg.StaticDir, "index.html"))
 }
 
 func main() {
+	// Carrega a configuração de flags, variáveis de ambiente e arquivo opcional
+	cfg, err := config.LoadGateway(os.Args[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		log.Fatalf("Erro ao carregar configuração: %v", err)
+	}
+
+	g, err := newGateway(cfg)
+	if err != nil {
+		log.Fatalf("Erro ao iniciar gateway: %v", err)
+	}
+	defer g.Close()
+
 	// Servir arquivos estáticos
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("./frontend"))))
+	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(cfg.StaticDir))))
 
 	// Rota para servir o index.html
-	http.HandleFunc("/", serveIndex)
+	http.HandleFunc("/", g.serveIndex)
 
+	// As rotas da API são limitadas por cliente (IP ou chave de API) e, com -auth-required, exigem
+	// um token com o escopo da rota; as estáticas e as de verificação ficam abertas
 	// Rota para buscar o clima via HTTP e gRPC
-	http.HandleFunc("/weather", handleWeather)
+	http.Handle("/weather", g.api(auth.ScopeWeather, g.handleWeather))
+
+	// Rota para buscar o clima de várias cidades de uma vez
+	http.Handle("/weather/batch", g.api(auth.ScopeWeather, g.handleWeatherBatch))
+
+	// Rota para receber atualizações de clima em tempo real (Server-Sent Events)
+	http.Handle("/weather/stream", g.api(auth.ScopeWeather, g.handleWeatherStream))
+
+	// Rota para consultar as observações registradas de uma cidade
+	http.Handle("/weather/history", g.api(auth.ScopeHistory, g.handleWeatherHistory))
+
+	// Rota para buscar a previsão de vários dias
+	http.Handle("/forecast", g.api(auth.ScopeForecast, g.handleForecast))
+
+	// Rota para buscar cidades pelo nome (autocompletar)
+	http.Handle("/cities", g.api(auth.ScopeWeather, g.handleCities))
+
+	// Rotas de verificação: processo no ar e conexão com o servidor gRPC pronta
+	http.HandleFunc("/healthz", g.handleHealthz)
+	http.HandleFunc("/readyz", g.handleReadyz)
 
-	log.Println("Servidor rodando em http://localhost:8080")
-	if err := http.ListenAndServe(":8080", nil); err != nil {
+	log.Printf("Servidor rodando em %s (gRPC em %s)", cfg.Addr, cfg.GRPCTarget)
+	if err := http.ListenAndServe(cfg.Addr, nil); err != nil {
 		log.Fatalf("Erro ao iniciar servidor: %v", err)
 	}
 }
@@ -2,106 +2,394 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net"
 	"net/http"
-	"net/url"
+	"os"
+	"strings"
+	"time"
 
+	"grpc-client/auth"
+	"grpc-client/config"
+	"grpc-client/ratelimit"
 	pb "grpc-client/web" // Ajuste para o caminho correto dos arquivos gerados
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
 )
 
-// Defina sua chave API do OpenWeather
-const apiKey = "858d50452536881dc0b2ce882156a3f8" // Insira sua chave de API aqui
-const apiURL = "http://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric"
-
-// Estrutura para resposta da API OpenWeather
-type WeatherAPIResponse struct {
-	Main struct {
-		Temp float32 `json:"temp"`
-	} `json:"main"`
-	Weather []struct {
-		Description string `json:"description"`
-	} `json:"weather"`
-}
-
 // Implementação do servidor gRPC
 type server struct {
 	pb.UnimplementedWeatherServiceServer
+
+	// Fornecedor de dados de clima usado para atender as requisições
+	provider WeatherProvider
+
+	// Fonte da busca de cidades usada em SearchCities
+	geocoder Geocoder
+
+	// Observador das cidades com assinaturas ativas (SubscribeWeather)
+	watcher *weatherWatcher
+
+	// Armazenamento das observações, consultado em GetHistory
+	store ObservationStore
+
+	// Quantidade máxima de consultas simultâneas em GetWeatherBatch
+	batchWorkers int
 }
 
-// Função que faz a chamada para a API do OpenWeather
-func getWeatherFromAPI(city string) (string, float32, error) {
-	// Codifica o nome da cidade corretamente para a URL
-	encodedCity := url.QueryEscape(city)
+// Implementação do método GetWeather do servidor gRPC
+func (s *server) GetWeather(ctx context.Context, req *pb.WeatherRequest) (*pb.WeatherResponse, error) {
+	query, err := weatherRequestQuery(req)
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
+	log.Printf("Recebendo requisição para cidade: %s", query)
 
-	// Monta a URL da API com a cidade e a chave de API
-	url := fmt.Sprintf(apiURL, encodedCity, apiKey)
+	// Obtém os dados reais do fornecedor configurado
+	weather, err := s.provider.CurrentWeather(ctx, query)
+	if err != nil {
+		return nil, toStatus(err, query.String())
+	}
+
+	// Retorna a resposta gRPC com os dados reais
+	return weatherToProto(weather), nil
+}
+
+// Implementação do método GetWeatherBatch do servidor gRPC
+// Cada cidade passa pelo mesmo caminho de GetWeather; os erros são reportados por cidade.
+func (s *server) GetWeatherBatch(ctx context.Context, req *pb.WeatherBatchRequest) (*pb.WeatherBatchResponse, error) {
+	log.Printf("Recebendo requisição em lote para %d cidades", len(req.Cities))
+
+	if err := validateBatch(req.Cities); err != nil {
+		return nil, toStatus(err, "")
+	}
+	if _, err := unitsFromProto(req.Units); err != nil {
+		return nil, toStatus(err, "")
+	}
+	if _, err := parseLocale(req.Locale); err != nil {
+		return nil, toStatus(err, "")
+	}
+
+	results := runBatch(ctx, req.Cities, s.batchWorkers, func(ctx context.Context, city string) (*pb.WeatherResponse, error) {
+		return s.GetWeather(ctx, &pb.WeatherRequest{
+			Location: &pb.WeatherRequest_City{City: city},
+			Units:    req.Units,
+			Locale:   req.Locale,
+		})
+	})
+	return &pb.WeatherBatchResponse{Results: results}, nil
+}
 
-	// Faz a requisição HTTP para a API do OpenWeather
-	resp, err := http.Get(url)
+// Implementação do método SubscribeWeather do servidor gRPC
+// Envia o clima atual e, em seguida, uma nova mensagem a cada mudança, até o cliente desconectar.
+func (s *server) SubscribeWeather(req *pb.WeatherRequest, stream pb.WeatherService_SubscribeWeatherServer) error {
+	query, err := weatherRequestQuery(req)
 	if err != nil {
-		return "", 0, fmt.Errorf("falha na requisição HTTP: %v", err)
+		return toStatus(err, "")
 	}
-	defer resp.Body.Close()
+	log.Printf("Nova assinatura de clima para cidade: %s", query)
 
-	// Lê a resposta da API
-	body, err := ioutil.ReadAll(resp.Body)
+	// Uma consulta inicial garante que a cidade existe antes de observá-la
+	if _, err := s.provider.CurrentWeather(stream.Context(), query); err != nil {
+		return toStatus(err, query.String())
+	}
+
+	updates, cancel := s.watcher.Subscribe(query)
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			log.Printf("Assinatura de clima encerrada para cidade: %s", query)
+			return nil
+		case weather := <-updates:
+			if err := stream.Send(weatherToProto(weather)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Converte o clima do fornecedor para a mensagem gRPC
+func weatherToProto(weather *Weather) *pb.WeatherResponse {
+	res := &pb.WeatherResponse{
+		City:           weather.City,
+		Country:        weather.Country,
+		CityId:         weather.CityID,
+		Coordinates:    weather.Coordinates.proto(),
+		Description:    weather.Description,
+		Temperature:    weather.Temperature,
+		Units:          weather.Units.proto(),
+		FeelsLike:      weather.FeelsLike,
+		TempMin:        weather.TempMin,
+		TempMax:        weather.TempMax,
+		Humidity:       weather.Humidity,
+		Pressure:       weather.Pressure,
+		WindSpeed:      weather.WindSpeed,
+		WindDirection:  weather.WindDirection,
+		CloudCover:     weather.CloudCover,
+		Visibility:     weather.Visibility,
+		TimezoneOffset: int32(weather.TimezoneOffset),
+		ConditionCode:  weather.ConditionCode,
+		Icon:           weather.Icon,
+		Source:         weather.Source,
+		Cached:         weather.Cached,
+		Stale:          weather.Stale,
+	}
+	if weather.Stale {
+		res.Age = int64(time.Since(weather.ObservedAt) / time.Second)
+	}
+	if !weather.ObservedAt.IsZero() {
+		res.ObservedAt = weather.ObservedAt.Unix()
+	}
+	if !weather.FetchedAt.IsZero() {
+		res.FetchedAt = weather.FetchedAt.Unix()
+	}
+	if !weather.Sunrise.IsZero() {
+		res.Sunrise = weather.Sunrise.Unix()
+	}
+	if !weather.Sunset.IsZero() {
+		res.Sunset = weather.Sunset.Unix()
+	}
+	return res
+}
+
+// Implementação do método GetForecast do servidor gRPC
+func (s *server) GetForecast(ctx context.Context, req *pb.ForecastRequest) (*pb.ForecastResponse, error) {
+	query, err := forecastRequestQuery(req)
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
+	log.Printf("Recebendo requisição de previsão para cidade: %s (%d dias)", query, req.Days)
+
+	days, err := forecastDays(req.Days)
 	if err != nil {
-		return "", 0, fmt.Errorf("falha ao ler a resposta: %v", err)
+		return nil, toStatus(err, query.String())
 	}
 
-	// Adicione este log para inspecionar a resposta
-	log.Printf("Resposta da API OpenWeather: %s", string(body))
+	forecast, err := s.provider.Forecast(ctx, query, days)
+	if err != nil {
+		return nil, toStatus(err, query.String())
+	}
+
+	return &pb.ForecastResponse{
+		City:        forecast.City,
+		Country:     forecast.Country,
+		CityId:      forecast.CityID,
+		Coordinates: forecast.Coordinates.proto(),
+		Hourly:      forecastEntriesToProto(forecast.Hourly),
+		Daily:       forecastEntriesToProto(forecast.Daily),
+		Units:       forecast.Units.proto(),
+		Source:      forecast.Source,
+	}, nil
+}
 
-	// Decodifica o JSON da resposta
-	var weatherData WeatherAPIResponse
-	err = json.Unmarshal(body, &weatherData)
+// Implementação do método SearchCities do servidor gRPC
+func (s *server) SearchCities(ctx context.Context, req *pb.SearchCitiesRequest) (*pb.SearchCitiesResponse, error) {
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, toStatus(&invalidArgumentError{Field: "query", Description: "o texto da busca deve ser informado"}, "")
+	}
+	limit, err := searchLimit(req.Limit)
 	if err != nil {
-		return "", 0, fmt.Errorf("falha ao decodificar JSON: %v", err)
+		return nil, toStatus(err, "")
 	}
 
-	// Retorna a descrição e a temperatura
-	description := weatherData.Weather[0].Description
-	temperature := weatherData.Main.Temp
+	cities, err := s.geocoder.SearchCities(ctx, req.Query, limit)
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
 
-	return description, temperature, nil
+	res := &pb.SearchCitiesResponse{Cities: make([]*pb.CityMatch, 0, len(cities))}
+	for _, c := range cities {
+		res.Cities = append(res.Cities, &pb.CityMatch{
+			Name:        c.Name,
+			Country:     c.Country,
+			Region:      c.Region,
+			Coordinates: c.Coordinates.proto(),
+		})
+	}
+	return res, nil
 }
 
-// Implementação do método GetWeather do servidor gRPC
-func (s *server) GetWeather(ctx context.Context, req *pb.WeatherRequest) (*pb.WeatherResponse, error) {
-	log.Printf("Recebendo requisição para cidade: %s", req.City)
+// Implementação do método GetHistory do servidor gRPC
+// Responde apenas com as observações que o próprio servidor obteve dos fornecedores.
+func (s *server) GetHistory(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	if strings.TrimSpace(req.City) == "" {
+		return nil, toStatus(&invalidArgumentError{Field: "city", Description: "a cidade deve ser informada"}, "")
+	}
+	if req.Country != "" && !isCountryCode(req.Country) {
+		return nil, toStatus(&invalidArgumentError{
+			Field:       "country",
+			Description: fmt.Sprintf("código de país inválido %q: use o código ISO 3166-1 de duas letras", req.Country),
+		}, "")
+	}
+	from, to, err := historyRange(req.From, req.To, time.Now())
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
+	bucket, granularity, err := historyBucket(req.Granularity)
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
+	units, err := unitsFromProto(req.Units)
+	if err != nil {
+		return nil, toStatus(err, "")
+	}
+	log.Printf("Recebendo requisição de histórico para cidade: %s (%s a %s)", req.City, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
 
-	// Obtém os dados reais da API
-	description, temperature, err := getWeatherFromAPI(req.City)
+	series, err := s.store.Query(req.City, req.Country, from, to)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao obter dados do clima: %v", err)
+		return nil, toStatus(err, req.City)
 	}
 
-	// Retorna a resposta gRPC com os dados reais
-	return &pb.WeatherResponse{
-		City:        req.City,
-		Description: description,
-		Temperature: temperature,
+	return &pb.HistoryResponse{
+		City:        series.city,
+		Country:     series.country,
+		Units:       units.proto(),
+		Granularity: granularity,
+		Points:      historyPoints(series, bucket, units),
 	}, nil
 }
 
+// Converte as entradas de previsão para o formato da mensagem gRPC
+func forecastEntriesToProto(entries []ForecastEntry) []*pb.ForecastEntry {
+	out := make([]*pb.ForecastEntry, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, &pb.ForecastEntry{
+			Time:                     e.Time.Unix(),
+			Temperature:              e.Temperature,
+			TempMin:                  e.TempMin,
+			TempMax:                  e.TempMax,
+			PrecipitationProbability: e.PrecipitationProbability,
+			WindSpeed:                e.WindSpeed,
+			Humidity:                 e.Humidity,
+			ConditionCode:            e.ConditionCode,
+			Description:              e.Description,
+		})
+	}
+	return out
+}
+
+// methodScopes define o escopo de token exigido por cada método quando a autenticação está ativa
+var methodScopes = map[string]string{
+	pb.WeatherService_GetWeather_FullMethodName:       auth.ScopeWeather,
+	pb.WeatherService_GetWeatherBatch_FullMethodName:  auth.ScopeWeather,
+	pb.WeatherService_SubscribeWeather_FullMethodName: auth.ScopeWeather,
+	pb.WeatherService_SearchCities_FullMethodName:     auth.ScopeWeather,
+	pb.WeatherService_GetForecast_FullMethodName:      auth.ScopeForecast,
+	pb.WeatherService_GetHistory_FullMethodName:       auth.ScopeHistory,
+}
+
 func main() {
-	// Cria um listener na porta 50051
-	lis, err := net.Listen("tcp", ":50051")
+	// Carrega a configuração de flags, variáveis de ambiente e arquivo opcional
+	cfg, err := config.LoadGRPC(os.Args[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		log.Fatalf("Falha ao carregar configuração: %v", err)
+	}
+
+	// Clientes HTTP dos fornecedores externos, com novas tentativas e circuit breaker
+	upstreams := &upstreamSet{}
+
+	// Seleção do fornecedor de clima na inicialização
+	provider, err := newProvider(cfg, upstreams)
+	if err != nil {
+		log.Fatalf("Falha ao configurar fornecedor: %v", err)
+	}
+	chain, _ := provider.(*chainProvider)
+
+	// Busca de cidades para o autocompletar do frontend
+	geocoder, err := newGeocoder(cfg, upstreams)
+	if err != nil {
+		log.Fatalf("Falha ao configurar busca de cidades: %v", err)
+	}
+
+	// Cada observação obtida do fornecedor é gravada no armazenamento, que alimenta o histórico
+	store, err := newStore(cfg)
+	if err != nil {
+		log.Fatalf("Falha ao abrir armazenamento de observações: %v", err)
+	}
+	defer store.Close()
+	go runCompaction(store, cfg.CompactInterval.Std())
+	provider = newHistoryProvider(provider, store)
+
+	// Respostas em cache economizam a cota da API nas cidades mais consultadas
+	var cache *cachedProvider
+	if cfg.CacheTTL > 0 {
+		cache = newCachedProvider(provider, cfg.CacheTTL.Std(), cfg.CacheSize)
+		provider = cache
+	}
+
+	// Com o fornecedor fora do ar, responde com a última observação gravada da cidade
+	if cfg.MaxStaleness > 0 {
+		provider = newStaleProvider(provider, store, cfg.MaxStaleness.Std())
+	}
+
+	if cfg.AdminAddr != "" {
+		admin := http.NewServeMux()
+		admin.HandleFunc("/admin/cache", handleCacheStats(cache))
+		admin.HandleFunc("/admin/providers", handleProviderStats(chain))
+		admin.HandleFunc("/admin/upstream", handleUpstreamStats(upstreams))
+		admin.HandleFunc("/admin/quota", handleQuotaStats(upstreams))
+		go serveAdmin(cfg.AdminAddr, admin)
+	}
+
+	// Cria o listener no endereço configurado
+	lis, err := net.Listen("tcp", cfg.Addr)
 	if err != nil {
 		log.Fatalf("Falha ao escutar: %v", err)
 	}
 
-	// Cria uma instância do servidor gRPC
-	s := grpc.NewServer()
-	pb.RegisterWeatherServiceServer(s, &server{})
+	// Limite de requisições por cliente final (IP ou chave de API)
+	limiter, err := ratelimit.NewFromConfig(cfg.RateLimitConfig, ratelimit.NewMemoryStore())
+	if err != nil {
+		log.Fatalf("Falha ao configurar limite de requisições: %v", err)
+	}
+
+	// O limite vem antes da autenticação, para que tokens inválidos também sejam contados
+	unary := []grpc.UnaryServerInterceptor{limiter.UnaryServerInterceptor(errorDomain)}
+	stream := []grpc.StreamServerInterceptor{limiter.StreamServerInterceptor(errorDomain)}
+	if cfg.AuthRequired {
+		authenticator, err := auth.NewFromConfig(cfg.AuthConfig, cfg.APIKeysFile)
+		if err != nil {
+			log.Fatalf("Falha ao configurar autenticação: %v", err)
+		}
+		unary = append(unary, authenticator.UnaryServerInterceptor(methodScopes, errorDomain))
+		stream = append(stream, authenticator.StreamServerInterceptor(methodScopes, errorDomain))
+	}
+
+	// Cria uma instância do servidor gRPC.
+	// Aceita os pings de keepalive do gateway, mesmo sem chamadas em andamento.
+	s := grpc.NewServer(
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             20 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+	pb.RegisterWeatherServiceServer(s, &server{
+		provider:     provider,
+		geocoder:     geocoder,
+		watcher:      newWeatherWatcher(provider, cfg.PollInterval.Std()),
+		store:        store,
+		batchWorkers: cfg.BatchWorkers,
+	})
+
+	// Health check consultado pelo gateway para saber se o serviço está pronto
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	healthServer.SetServingStatus(pb.WeatherService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
 
-	log.Printf("Servidor gRPC rodando na porta 50051")
+	log.Printf("Servidor gRPC rodando em %s (fornecedor: %s, busca de cidades: %s, armazenamento: %s)", cfg.Addr, provider.Name(), geocoder.Name(), store.Name())
+	log.Printf("Chamadas aos fornecedores levam até %s; o timeout do gateway deve ser maior", cfg.UpstreamBudget())
 
 	// Inicia o servidor gRPC
 	if err := s.Serve(lis); err != nil {
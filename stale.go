@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// staleProvider envolve outro WeatherProvider com a política stale-if-error: quando o
+// fornecedor falha, responde com a última observação gravada do local, marcada como
+// desatualizada, desde que ela não seja mais antiga que maxAge.
+// Fica acima do cache, para que a observação antiga não seja guardada nele.
+type staleProvider struct {
+	WeatherProvider
+
+	store  ObservationStore
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func newStaleProvider(provider WeatherProvider, store ObservationStore, maxAge time.Duration) *staleProvider {
+	return &staleProvider{WeatherProvider: provider, store: store, maxAge: maxAge, now: time.Now}
+}
+
+// Antecedência, em relação ao prazo do cliente, com que a consulta ao fornecedor é interrompida
+// para que ainda dê tempo de responder com a observação gravada
+const staleReserve = 250 * time.Millisecond
+
+// CurrentWeather consulta o fornecedor e, se ele estiver indisponível, recorre ao armazenamento.
+// Erros do cliente (ex.: cidade inexistente) e requisições canceladas são devolvidos sem alteração.
+func (p *staleProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	callCtx, cancel := staleDeadline(ctx)
+	defer cancel()
+
+	weather, err := p.WeatherProvider.CurrentWeather(callCtx, q)
+	if err == nil || ctx.Err() != nil || !isUpstreamFailure(err) {
+		return weather, err
+	}
+
+	rec, ok := p.store.Latest(q)
+	if !ok {
+		return nil, err
+	}
+	if age := p.now().Sub(rec.Time); age > p.maxAge {
+		log.Printf("Fornecedor falhou para %s e a última observação (%s) passou da idade máxima", q, age.Round(time.Second))
+		return nil, err
+	}
+
+	log.Printf("Fornecedor falhou para %s (%v); respondendo com a observação de %s", q, err, rec.Time.Format(time.RFC3339))
+	stale := rec.weather(q.Units)
+	stale.Stale = true
+	return stale, nil
+}
+
+// staleDeadline retorna o contexto da consulta ao fornecedor, com o prazo do cliente
+// antecipado em staleReserve. Um fornecedor que trava em vez de recusar a conexão estoura
+// esse prazo mais curto, e a observação gravada ainda chega ao cliente dentro do dele.
+// Sem prazo, ou com prazo curto demais para a reserva, vale o contexto do cliente.
+func staleDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || time.Until(deadline) <= 2*staleReserve {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, deadline.Add(-staleReserve))
+}
+
+// isUpstreamFailure indica se o erro é uma falha do fornecedor, e não da consulta do cliente
+func isUpstreamFailure(err error) bool {
+	return errors.Is(err, errUpstreamUnavailable) ||
+		errors.Is(err, errUpstreamRateLimited) ||
+		errors.Is(err, errUpstreamBadResponse) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
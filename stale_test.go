@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// hangingProvider simula um fornecedor que não responde: espera até o prazo da consulta
+type hangingProvider struct {
+	WeatherProvider
+}
+
+func (hangingProvider) CurrentWeather(ctx context.Context, q WeatherQuery) (*Weather, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStaleProviderServesStoredObservationWhenProviderHangs(t *testing.T) {
+	store := newMemoryStore(retentionPolicy{maxPerCity: 10})
+	store.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, time.Now().Add(-time.Hour), 28))
+	p := newStaleProvider(hangingProvider{}, store, 6*time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	weather, err := p.CurrentWeather(ctx, WeatherQuery{City: "Recife", Units: unitsMetric})
+	if err != nil {
+		t.Fatalf("CurrentWeather: %v, quer a observação gravada", err)
+	}
+	if !weather.Stale || weather.Temperature != 28 {
+		t.Errorf("CurrentWeather = %.0f (stale %v), quer 28 desatualizado", weather.Temperature, weather.Stale)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("a resposta chegou depois do prazo do cliente")
+	}
+}
+
+func TestStaleProviderWithoutObservation(t *testing.T) {
+	p := newStaleProvider(hangingProvider{}, newMemoryStore(retentionPolicy{maxPerCity: 10}), 6*time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := p.CurrentWeather(ctx, WeatherQuery{City: "Recife", Units: unitsMetric})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("erro = %v, quer context.DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"grpc-client/config"
+)
+
+// ObservationStore é a interface implementada pelos armazenamentos de observações.
+// Cada observação obtida dos fornecedores é gravada com a cidade resolvida, o horário
+// e o fornecedor de origem, e fica disponível para o histórico (GetHistory).
+type ObservationStore interface {
+	// Name retorna o identificador do armazenamento (ex.: "file").
+	Name() string
+	// Append grava a observação. Uma observação da mesma cidade no mesmo horário substitui a anterior.
+	Append(rec observationRecord) error
+	// Query retorna as observações da cidade no intervalo [from, to), em ordem cronológica.
+	// Sem o país, o nome precisa identificar uma única cidade.
+	Query(city, country string, from, to time.Time) (*citySeries, error)
+	// Latest retorna a observação mais recente do local consultado (pelo ID da cidade,
+	// pelas coordenadas ou pelo nome), usada quando o fornecedor falha.
+	Latest(q WeatherQuery) (observationRecord, bool)
+	// Compact descarta as observações fora da política de retenção e retorna quantas foram removidas.
+	Compact(now time.Time) (int, error)
+	// Close libera os recursos do armazenamento.
+	Close() error
+}
+
+// Nomes dos armazenamentos aceitos na configuração
+const (
+	storeMemory = "memory"
+	storeFile   = "file"
+)
+
+// observationRecord é uma observação gravada no armazenamento, sempre em unidades
+// métricas (°C e m/s), para que consultas em unidades diferentes compartilhem a mesma série.
+type observationRecord struct {
+	City           string    `json:"city"`
+	Country        string    `json:"country,omitempty"`
+	CityID         int64     `json:"cityId,omitempty"`
+	Lat            float64   `json:"lat"`
+	Lon            float64   `json:"lon"`
+	Time           time.Time `json:"time"` // Momento da observação (ou da consulta, se o fornecedor não informar)
+	FetchedAt      time.Time `json:"fetchedAt"`
+	Source         string    `json:"source"`
+	TimezoneOffset int       `json:"timezoneOffset"`
+
+	Description   string    `json:"description"`
+	Temperature   float32   `json:"temperature"`
+	FeelsLike     float32   `json:"feelsLike"`
+	TempMin       float32   `json:"tempMin"`
+	TempMax       float32   `json:"tempMax"`
+	Humidity      int32     `json:"humidity"`
+	Pressure      int32     `json:"pressure"`
+	WindSpeed     float32   `json:"windSpeed"`
+	WindDirection int32     `json:"windDirection"`
+	CloudCover    int32     `json:"cloudCover"`
+	Visibility    int32     `json:"visibility"`
+	Sunrise       time.Time `json:"sunrise"`
+	Sunset        time.Time `json:"sunset"`
+	ConditionCode int32     `json:"conditionCode"`
+	Icon          string    `json:"icon,omitempty"`
+}
+
+// newObservationRecord converte o clima obtido do fornecedor para o registro gravado.
+// Retorna false quando falta a cidade ou o horário da observação.
+func newObservationRecord(w *Weather) (observationRecord, bool) {
+	at := w.ObservedAt
+	if at.IsZero() {
+		at = w.FetchedAt
+	}
+	if w.City == "" || at.IsZero() {
+		return observationRecord{}, false
+	}
+	return observationRecord{
+		City:           w.City,
+		Country:        w.Country,
+		CityID:         w.CityID,
+		Lat:            w.Coordinates.Lat,
+		Lon:            w.Coordinates.Lon,
+		Time:           at.UTC(),
+		FetchedAt:      w.FetchedAt.UTC(),
+		Source:         w.Source,
+		TimezoneOffset: w.TimezoneOffset,
+
+		Description:   w.Description,
+		Temperature:   temperatureToCelsius(w.Temperature, w.Units),
+		FeelsLike:     temperatureToCelsius(w.FeelsLike, w.Units),
+		TempMin:       temperatureToCelsius(w.TempMin, w.Units),
+		TempMax:       temperatureToCelsius(w.TempMax, w.Units),
+		Humidity:      w.Humidity,
+		Pressure:      w.Pressure,
+		WindSpeed:     speedToMetric(w.WindSpeed, w.Units),
+		WindDirection: w.WindDirection,
+		CloudCover:    w.CloudCover,
+		Visibility:    w.Visibility,
+		Sunrise:       w.Sunrise.UTC(),
+		Sunset:        w.Sunset.UTC(),
+		ConditionCode: w.ConditionCode,
+		Icon:          w.Icon,
+	}, true
+}
+
+// weather converte o registro de volta para o clima na unidade informada
+func (r observationRecord) weather(u Units) *Weather {
+	w := &Weather{
+		City:           r.City,
+		Country:        r.Country,
+		CityID:         r.CityID,
+		Coordinates:    Coordinates{Lat: r.Lat, Lon: r.Lon},
+		Description:    r.Description,
+		Temperature:    r.Temperature,
+		FeelsLike:      r.FeelsLike,
+		TempMin:        r.TempMin,
+		TempMax:        r.TempMax,
+		Humidity:       r.Humidity,
+		Pressure:       r.Pressure,
+		WindSpeed:      r.WindSpeed,
+		WindDirection:  r.WindDirection,
+		CloudCover:     r.CloudCover,
+		Visibility:     r.Visibility,
+		Sunrise:        r.Sunrise,
+		Sunset:         r.Sunset,
+		TimezoneOffset: r.TimezoneOffset,
+		ConditionCode:  r.ConditionCode,
+		Icon:           r.Icon,
+		ObservedAt:     r.Time,
+		FetchedAt:      r.FetchedAt,
+		Source:         r.Source,
+	}
+	convertWeather(w, u)
+	return w
+}
+
+// citySeries reúne as observações de uma cidade em ordem cronológica
+type citySeries struct {
+	city           string
+	country        string
+	cityID         int64 // ID da cidade no fornecedor (0 se desconhecido)
+	timezoneOffset int
+	records        []observationRecord
+}
+
+// latest retorna a observação mais recente da série, que não pode estar vazia
+func (s *citySeries) latest() observationRecord {
+	return s.records[len(s.records)-1]
+}
+
+// retentionPolicy define quais observações o armazenamento mantém:
+// no máximo maxPerCity por cidade e nenhuma mais antiga que maxAge (zero não limita a idade).
+type retentionPolicy struct {
+	maxPerCity int
+	maxAge     time.Duration
+}
+
+// keep retorna as observações (em ordem cronológica) que continuam dentro da política
+func (p retentionPolicy) keep(records []observationRecord, now time.Time) []observationRecord {
+	if p.maxAge > 0 {
+		cutoff := now.Add(-p.maxAge)
+		i := 0
+		for i < len(records) && records[i].Time.Before(cutoff) {
+			i++
+		}
+		records = records[i:]
+	}
+	if p.maxPerCity > 0 && len(records) > p.maxPerCity {
+		records = records[len(records)-p.maxPerCity:]
+	}
+	return records
+}
+
+// newStore cria o armazenamento de observações selecionado pela configuração de inicialização
+func newStore(cfg *config.GRPCConfig) (ObservationStore, error) {
+	policy := retentionPolicy{maxPerCity: cfg.HistorySize, maxAge: cfg.HistoryRetention.Std()}
+	switch cfg.Store {
+	case storeMemory:
+		return newMemoryStore(policy), nil
+	case storeFile:
+		return openFileStore(cfg.StorePath, policy)
+	default:
+		return nil, fmt.Errorf("armazenamento de observações desconhecido: %q", cfg.Store)
+	}
+}
+
+// runCompaction compacta o armazenamento periodicamente. Roda durante toda a vida do servidor.
+func runCompaction(store ObservationStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		removed, err := store.Compact(now)
+		if err != nil {
+			log.Printf("Erro ao compactar observações: %v", err)
+			continue
+		}
+		if removed > 0 {
+			log.Printf("Compactação das observações: %d removidas", removed)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileStore implementa ObservationStore em um arquivo local no formato JSON Lines
+// (uma observação por linha). As gravações são acrescentadas ao fim do arquivo e as
+// consultas são atendidas por um índice em memória, carregado do arquivo ao iniciar.
+// A compactação aplica a política de retenção e reescreve o arquivo apenas com as
+// observações mantidas, eliminando também as linhas substituídas.
+type fileStore struct {
+	index *memoryStore
+	path  string
+
+	mu     sync.Mutex // protege os campos abaixo; Append e Compact não se intercalam
+	file   *os.File
+	lines  int // linhas gravadas no arquivo, incluindo as já substituídas no índice
+	closed bool
+}
+
+// openFileStore abre (ou cria) o arquivo de observações, carrega o índice e compacta o arquivo
+func openFileStore(path string, policy retentionPolicy) (*fileStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("falha ao criar diretório das observações: %v", err)
+	}
+
+	s := &fileStore{index: newMemoryStore(policy), path: path}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if _, err := s.Compact(time.Now()); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileStore) Name() string {
+	return storeFile
+}
+
+// load lê as observações do arquivo para o índice. Linhas inválidas (ex.: a última linha
+// truncada por uma queda do servidor) são ignoradas e desaparecem na compactação seguinte.
+func (s *fileStore) load() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("falha ao abrir arquivo de observações: %v", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var rec observationRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			log.Printf("Ignorando linha %d inválida em %s: %v", line, s.path, err)
+			continue
+		}
+		s.index.Append(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("falha ao ler arquivo de observações: %v", err)
+	}
+	s.lines = line
+	return nil
+}
+
+// Append grava a observação no fim do arquivo e no índice
+func (s *fileStore) Append(rec observationRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("arquivo de observações indisponível")
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("falha ao gravar observação: %v", err)
+	}
+	s.lines++
+	return s.index.Append(rec)
+}
+
+func (s *fileStore) Query(city, country string, from, to time.Time) (*citySeries, error) {
+	return s.index.Query(city, country, from, to)
+}
+
+func (s *fileStore) Latest(q WeatherQuery) (observationRecord, bool) {
+	return s.index.Latest(q)
+}
+
+// Compact aplica a política de retenção ao índice e, se alguma linha do arquivo deixou
+// de ser necessária, reescreve o arquivo em um temporário que substitui o original.
+func (s *fileStore) Compact(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, nil
+	}
+
+	removed, err := s.index.Compact(now)
+	if err != nil {
+		return 0, err
+	}
+
+	records := s.index.snapshot()
+	if s.file != nil && s.lines == len(records) {
+		return removed, nil
+	}
+	return removed, s.rewrite(records)
+}
+
+// rewrite substitui o arquivo pelas observações informadas e passa a acrescentar no novo arquivo.
+// O arquivo atual só é trocado depois que a substituição dá certo; em caso de falha, as
+// gravações continuam no arquivo original.
+func (s *fileStore) rewrite(records []observationRecord) (err error) {
+	defer func() {
+		if err != nil && s.file == nil {
+			if openErr := s.openAppend(); openErr != nil {
+				log.Printf("Falha ao reabrir arquivo de observações: %v", openErr)
+			}
+		}
+	}()
+
+	// O temporário é aberto para acréscimos e, depois de renomeado, recebe as novas gravações
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("falha ao criar arquivo de compactação: %v", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err = enc.Encode(rec); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("falha ao gravar arquivo de compactação: %v", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("falha ao substituir arquivo de observações: %v", err)
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file = f
+	s.lines = len(records)
+	return nil
+}
+
+// openAppend abre o arquivo de observações para acréscimos
+func (s *fileStore) openAppend() error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir arquivo de observações: %v", err)
+	}
+	s.file = file
+	return nil
+}
+
+func (s *fileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countLines conta as linhas do arquivo de observações
+func countLines(t *testing.T, path string) int {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("abrindo %s: %v", path, err)
+	}
+	defer f.Close()
+
+	n := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		n++
+	}
+	return n
+}
+
+func TestFileStoreCompactRewritesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "observations.jsonl")
+	base := time.Now().UTC().Truncate(time.Second)
+	policy := retentionPolicy{maxPerCity: 2}
+
+	s, err := openFileStore(path, policy)
+	if err != nil {
+		t.Fatalf("openFileStore: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base.Add(time.Duration(i)*time.Minute), float32(20+i))); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	// A mesma observação gravada de novo substitui a anterior no índice, mas ocupa outra linha
+	s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base.Add(3*time.Minute), 30))
+	if n := countLines(t, path); n != 5 {
+		t.Fatalf("arquivo com %d linhas antes da compactação, quer 5", n)
+	}
+
+	if _, err := s.Compact(time.Now()); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if n := countLines(t, path); n != 2 {
+		t.Errorf("arquivo com %d linhas depois da compactação, quer 2", n)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("o arquivo temporário da compactação ficou no disco")
+	}
+
+	// As gravações seguintes vão para o arquivo que substituiu o original
+	if err := s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base.Add(4*time.Minute), 31)); err != nil {
+		t.Fatalf("Append depois da compactação: %v", err)
+	}
+	s.Close()
+	if n := countLines(t, path); n != 3 {
+		t.Errorf("arquivo com %d linhas depois da nova gravação, quer 3", n)
+	}
+
+	reopened, err := openFileStore(path, policy)
+	if err != nil {
+		t.Fatalf("reabrindo: %v", err)
+	}
+	defer reopened.Close()
+	rec, ok := reopened.Latest(WeatherQuery{CityID: 3390760})
+	if !ok || rec.Temperature != 31 {
+		t.Errorf("Latest depois de reabrir = %.0f (%v), quer 31", rec.Temperature, ok)
+	}
+}
+
+func TestFileStoreCompactFailureKeepsAppending(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "observations.jsonl")
+	base := time.Now().UTC().Truncate(time.Second)
+
+	s, err := openFileStore(path, retentionPolicy{maxPerCity: 1})
+	if err != nil {
+		t.Fatalf("openFileStore: %v", err)
+	}
+	defer s.Close()
+	s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base, 20))
+	s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base.Add(time.Minute), 21))
+
+	// Um diretório no lugar do temporário faz a compactação falhar ao criá-lo
+	if err := os.Mkdir(path+".tmp", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Compact(time.Now()); err == nil {
+		t.Fatalf("Compact sem erro, quer falha ao criar o temporário")
+	}
+
+	if err := s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base.Add(2*time.Minute), 22)); err != nil {
+		t.Fatalf("Append depois da falha na compactação: %v", err)
+	}
+	if n := countLines(t, path); n != 3 {
+		t.Errorf("arquivo original com %d linhas, quer 3 (as gravações continuam nele)", n)
+	}
+
+	// Sem o obstáculo, a compactação seguinte funciona
+	os.Remove(path + ".tmp")
+	if _, err := s.Compact(time.Now()); err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if n := countLines(t, path); n != 1 {
+		t.Errorf("arquivo com %d linhas depois da compactação, quer 1", n)
+	}
+}
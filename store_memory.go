@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryStore implementa ObservationStore em memória, agrupando as observações pela
+// cidade resolvida (nome canônico e país), com um índice pelo ID da cidade no fornecedor.
+// Os dados se perdem ao reiniciar o servidor.
+type memoryStore struct {
+	policy retentionPolicy
+
+	mu     sync.RWMutex
+	series map[string]*citySeries
+	ids    map[int64]string // ID da cidade -> chave da série
+}
+
+func newMemoryStore(policy retentionPolicy) *memoryStore {
+	return &memoryStore{policy: policy, series: make(map[string]*citySeries), ids: make(map[int64]string)}
+}
+
+func (s *memoryStore) Name() string {
+	return storeMemory
+}
+
+// seriesKey gera a chave da cidade no armazenamento (ex.: "sao paulo,br")
+func seriesKey(city, country string) string {
+	return normalizeCity(city) + "," + strings.ToLower(country)
+}
+
+// Append insere a observação na posição cronológica. A mesma observação obtida mais de uma
+// vez (outra unidade, outro idioma ou uma nova consulta antes de o fornecedor atualizar
+// os dados) é guardada uma só vez. O limite por cidade é aplicado a cada inserção.
+func (s *memoryStore) Append(rec observationRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := seriesKey(rec.City, rec.Country)
+	series, ok := s.series[key]
+	if !ok {
+		series = &citySeries{city: rec.City, country: rec.Country}
+		s.series[key] = series
+	}
+	series.timezoneOffset = rec.TimezoneOffset
+	if rec.CityID != 0 {
+		series.cityID = rec.CityID
+		s.ids[rec.CityID] = key
+	}
+
+	records := series.records
+	i := sort.Search(len(records), func(i int) bool { return !records[i].Time.Before(rec.Time) })
+	if i < len(records) && records[i].Time.Equal(rec.Time) {
+		records[i] = rec
+		return nil
+	}
+	records = append(records, observationRecord{})
+	copy(records[i+1:], records[i:])
+	records[i] = rec
+
+	if s.policy.maxPerCity > 0 && len(records) > s.policy.maxPerCity {
+		records = append(records[:0], records[len(records)-s.policy.maxPerCity:]...)
+	}
+	series.records = records
+	return nil
+}
+
+func (s *memoryStore) Query(city, country string, from, to time.Time) (*citySeries, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var series *citySeries
+	if country != "" {
+		series = s.series[seriesKey(city, country)]
+	} else {
+		prefix := normalizeCity(city) + ","
+		var countries []string
+		for key, candidate := range s.series {
+			if strings.HasPrefix(key, prefix) {
+				series = candidate
+				countries = append(countries, candidate.country)
+			}
+		}
+		if len(countries) > 1 {
+			sort.Strings(countries)
+			return nil, &invalidArgumentError{
+				Field:       "country",
+				Description: fmt.Sprintf("há observações de %s em mais de um país (%s): informe o país", city, strings.Join(countries, ", ")),
+			}
+		}
+	}
+	if series == nil {
+		return nil, fmt.Errorf("%w: nenhuma observação registrada para %s", errCityNotFound, city)
+	}
+
+	records := series.records
+	start := sort.Search(len(records), func(i int) bool { return !records[i].Time.Before(from) })
+	end := sort.Search(len(records), func(i int) bool { return !records[i].Time.Before(to) })
+	out := &citySeries{city: series.city, country: series.country, timezoneOffset: series.timezoneOffset}
+	if start < end {
+		out.records = append([]observationRecord(nil), records[start:end]...)
+	}
+	return out, nil
+}
+
+// Distância máxima entre as coordenadas pedidas e a cidade gravada para que Latest a considere
+const latestMaxDistanceKm = 50
+
+// Latest retorna a última observação da cidade consultada. O ID da cidade é buscado no índice
+// de IDs e as coordenadas são antes resolvidas para a cidade gravada mais próxima (ver nearest).
+// Pelo nome sem o país, havendo mais de uma cidade, vence a observação mais recente.
+func (s *memoryStore) Latest(q WeatherQuery) (observationRecord, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var series *citySeries
+	switch {
+	case q.CityID != 0:
+		if key, ok := s.ids[q.CityID]; ok {
+			series = s.series[key]
+		}
+	case q.Coordinates != nil:
+		series = s.nearest(*q.Coordinates)
+	case q.Country != "":
+		series = s.series[seriesKey(q.City, q.Country)]
+	default:
+		prefix := normalizeCity(q.City) + ","
+		for key, candidate := range s.series {
+			if strings.HasPrefix(key, prefix) && len(candidate.records) > 0 &&
+				(series == nil || candidate.latest().Time.After(series.latest().Time)) {
+				series = candidate
+			}
+		}
+	}
+	if series == nil || len(series.records) == 0 {
+		return observationRecord{}, false
+	}
+	return series.latest(), true
+}
+
+// nearest resolve as coordenadas para a cidade gravada mais próxima, desde que a até
+// latestMaxDistanceKm: a posição da última observação de cada cidade é a do fornecedor,
+// que raramente coincide com a do navegador ou a do índice de cidades do autocompletar.
+func (s *memoryStore) nearest(c Coordinates) *citySeries {
+	var best *citySeries
+	bestDistance := float64(latestMaxDistanceKm)
+	for _, series := range s.series {
+		if len(series.records) == 0 {
+			continue
+		}
+		last := series.latest()
+		if d := distanceKm(c, Coordinates{Lat: last.Lat, Lon: last.Lon}); d <= bestDistance {
+			best, bestDistance = series, d
+		}
+	}
+	return best
+}
+
+// Compact aplica a política de retenção a todas as cidades, removendo as que ficaram vazias
+func (s *memoryStore) Compact(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for key, series := range s.series {
+		kept := s.policy.keep(series.records, now)
+		if len(kept) == len(series.records) {
+			continue
+		}
+		removed += len(series.records) - len(kept)
+		if len(kept) == 0 {
+			delete(s.series, key)
+			if s.ids[series.cityID] == key {
+				delete(s.ids, series.cityID)
+			}
+			continue
+		}
+		series.records = append([]observationRecord(nil), kept...)
+	}
+	return removed, nil
+}
+
+// snapshot retorna uma cópia de todas as observações, agrupadas por cidade
+func (s *memoryStore) snapshot() []observationRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var out []observationRecord
+	for _, series := range s.series {
+		out = append(out, series.records...)
+	}
+	return out
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// testRecord cria uma observação métrica da cidade no horário informado
+func testRecord(city, country string, id int64, lat, lon float64, at time.Time, temp float32) observationRecord {
+	return observationRecord{
+		City:        city,
+		Country:     country,
+		CityID:      id,
+		Lat:         lat,
+		Lon:         lon,
+		Time:        at,
+		FetchedAt:   at,
+		Source:      providerFixture,
+		Temperature: temp,
+	}
+}
+
+func TestMemoryStoreLatest(t *testing.T) {
+	base := time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+	s := newMemoryStore(retentionPolicy{maxPerCity: 10})
+	for _, rec := range []observationRecord{
+		testRecord("São Paulo", "BR", 3448439, -23.5475, -46.6361, base, 20),
+		testRecord("São Paulo", "BR", 3448439, -23.5475, -46.6361, base.Add(time.Hour), 21),
+		testRecord("Springfield", "US", 4409896, 37.2153, -93.2982, base, 15),
+		testRecord("Springfield", "AU", 0, -27.6536, 152.9171, base.Add(2*time.Hour), 25),
+	} {
+		if err := s.Append(rec); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    WeatherQuery
+		wantCity string
+		wantTemp float32
+		wantOK   bool
+	}{
+		{"nome sem acento", WeatherQuery{City: "sao paulo"}, "São Paulo", 21, true},
+		{"nome e país", WeatherQuery{City: "Springfield", Country: "us"}, "Springfield", 15, true},
+		{"nome em mais de um país", WeatherQuery{City: "Springfield"}, "Springfield", 25, true},
+		{"ID da cidade", WeatherQuery{CityID: 3448439}, "São Paulo", 21, true},
+		{"ID desconhecido", WeatherQuery{CityID: 1}, "", 0, false},
+		{"coordenadas próximas", WeatherQuery{Coordinates: &Coordinates{Lat: -23.6, Lon: -46.7}}, "São Paulo", 21, true},
+		{"coordenadas distantes", WeatherQuery{Coordinates: &Coordinates{Lat: -22.9, Lon: -43.2}}, "", 0, false},
+		{"cidade sem observações", WeatherQuery{City: "Recife"}, "", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, ok := s.Latest(tt.query)
+			if ok != tt.wantOK {
+				t.Fatalf("Latest(%s) ok = %v, quer %v", tt.query, ok, tt.wantOK)
+			}
+			if ok && (rec.City != tt.wantCity || rec.Temperature != tt.wantTemp) {
+				t.Errorf("Latest(%s) = %s %.0f, quer %s %.0f", tt.query, rec.City, rec.Temperature, tt.wantCity, tt.wantTemp)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreCompactRemovesCityID(t *testing.T) {
+	base := time.Date(2024, 9, 14, 12, 0, 0, 0, time.UTC)
+	s := newMemoryStore(retentionPolicy{maxPerCity: 10, maxAge: time.Hour})
+	s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, base, 28))
+
+	removed, err := s.Compact(base.Add(2 * time.Hour))
+	if err != nil || removed != 1 {
+		t.Fatalf("Compact = %d, %v, quer 1 removida", removed, err)
+	}
+	if _, ok := s.Latest(WeatherQuery{CityID: 3390760}); ok {
+		t.Errorf("Latest pelo ID encontrou a cidade removida na compactação")
+	}
+}
+
+func TestMemoryStoreQueryRange(t *testing.T) {
+	day := time.Date(2024, 9, 14, 0, 0, 0, 0, time.UTC)
+	s := newMemoryStore(retentionPolicy{maxPerCity: 10})
+	for _, at := range []time.Time{day.Add(-time.Second), day, day.Add(23 * time.Hour), day.AddDate(0, 0, 1)} {
+		s.Append(testRecord("Recife", "BR", 3390760, -8.0539, -34.8811, at, 28))
+	}
+
+	// O fim do intervalo é exclusivo: a meia-noite do dia seguinte fica de fora
+	series, err := s.Query("recife", "", day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(series.records) != 2 || !series.records[0].Time.Equal(day) || !series.records[1].Time.Equal(day.Add(23*time.Hour)) {
+		t.Errorf("Query retornou %d observações, quer as 2 do dia 14", len(series.records))
+	}
+}
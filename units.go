@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+
+	pb "grpc-client/web"
+)
+
+// Units é a unidade de medida das respostas, com os mesmos nomes
+// do parâmetro "units" da API do OpenWeather.
+type Units string
+
+const (
+	unitsMetric   Units = "metric"   // °C e m/s
+	unitsImperial Units = "imperial" // °F e mph
+	unitsStandard Units = "standard" // Kelvin e m/s
+)
+
+// unitsFromProto converte a unidade pedida pelo cliente, usando o sistema métrico quando não informada
+func unitsFromProto(u pb.Units) (Units, error) {
+	switch u {
+	case pb.Units_UNITS_UNSPECIFIED, pb.Units_UNITS_METRIC:
+		return unitsMetric, nil
+	case pb.Units_UNITS_IMPERIAL:
+		return unitsImperial, nil
+	case pb.Units_UNITS_STANDARD:
+		return unitsStandard, nil
+	default:
+		return "", &invalidArgumentError{Field: "units", Description: fmt.Sprintf("unidade desconhecida: %d", u)}
+	}
+}
+
+// proto converte a unidade para o enum da mensagem gRPC
+func (u Units) proto() pb.Units {
+	switch u {
+	case unitsImperial:
+		return pb.Units_UNITS_IMPERIAL
+	case unitsStandard:
+		return pb.Units_UNITS_STANDARD
+	default:
+		return pb.Units_UNITS_METRIC
+	}
+}
+
+// convertTemperature converte uma temperatura em °C para a unidade informada
+func convertTemperature(celsius float32, u Units) float32 {
+	switch u {
+	case unitsImperial:
+		return celsius*9/5 + 32
+	case unitsStandard:
+		return celsius + 273.15
+	default:
+		return celsius
+	}
+}
+
+// convertSpeed converte uma velocidade em m/s para a unidade informada
+func convertSpeed(ms float32, u Units) float32 {
+	if u == unitsImperial {
+		return ms * 2.236936
+	}
+	return ms
+}
+
+// temperatureToCelsius converte uma temperatura na unidade informada para °C
+func temperatureToCelsius(t float32, u Units) float32 {
+	switch u {
+	case unitsImperial:
+		return (t - 32) * 5 / 9
+	case unitsStandard:
+		return t - 273.15
+	default:
+		return t
+	}
+}
+
+// speedToMetric converte uma velocidade na unidade informada para m/s
+func speedToMetric(s float32, u Units) float32 {
+	if u == unitsImperial {
+		return s / 2.236936
+	}
+	return s
+}
+
+// convertWeather converte uma observação em unidades métricas para a unidade informada.
+// Usada por fornecedores que só trabalham com o sistema métrico (ex.: fixtures).
+func convertWeather(w *Weather, u Units) {
+	w.Temperature = convertTemperature(w.Temperature, u)
+	w.FeelsLike = convertTemperature(w.FeelsLike, u)
+	w.TempMin = convertTemperature(w.TempMin, u)
+	w.TempMax = convertTemperature(w.TempMax, u)
+	w.WindSpeed = convertSpeed(w.WindSpeed, u)
+	w.Units = u
+}
+
+// convertForecast converte uma previsão em unidades métricas para a unidade informada
+func convertForecast(f *Forecast, u Units) {
+	for _, entries := range [][]ForecastEntry{f.Hourly, f.Daily} {
+		for i := range entries {
+			e := &entries[i]
+			e.Temperature = convertTemperature(e.Temperature, u)
+			e.TempMin = convertTemperature(e.TempMin, u)
+			e.TempMax = convertTemperature(e.TempMax, u)
+			e.WindSpeed = convertSpeed(e.WindSpeed, u)
+		}
+	}
+	f.Units = u
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"grpc-client/config"
+)
+
+// Espera máxima entre tentativas. Um Retry-After maior que isso encerra as tentativas.
+const upstreamMaxBackoff = 5 * time.Second
+
+// upstreamResponse é a resposta HTTP do fornecedor, com o corpo já lido
+type upstreamResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// upstreamStats reúne os contadores de um cliente upstream, expostos no endpoint administrativo
+type upstreamStats struct {
+	Breaker    string `json:"breaker"`
+	Requests   uint64 `json:"requests"`
+	Attempts   uint64 `json:"attempts"`
+	Retries    uint64 `json:"retries"`
+	Failures   uint64 `json:"failures"`
+	Rejected   uint64 `json:"rejected"`
+	Opened     uint64 `json:"opened"`
+	HalfOpened uint64 `json:"halfOpened"`
+	Closed     uint64 `json:"closed"`
+}
+
+// upstreamClient é o cliente HTTP das chamadas a um fornecedor externo.
+// Cada tentativa tem o seu próprio timeout, dentro do prazo da requisição do cliente;
+// falhas transitórias (rede, timeout, HTTP 5xx) de requisições idempotentes são repetidas
+// com backoff exponencial e jitter, e um circuit breaker recusa as chamadas de imediato
+// enquanto o fornecedor estiver com problemas. Cada tentativa consome a cota da chave (quota).
+type upstreamClient struct {
+	name    string
+	client  *http.Client
+	timeout time.Duration
+	retries int
+	backoff time.Duration
+	breaker *circuitBreaker
+	quota   *upstreamQuota // nil não limita
+	// Parâmetros da URL que não podem aparecer nos logs (ex.: a chave de API)
+	secretParams []string
+
+	requests, attempts, retried, failures, rejected atomic.Uint64
+	opened, halfOpened, closed                      atomic.Uint64
+}
+
+func newUpstreamClient(name string, cfg *config.GRPCConfig, quota *upstreamQuota, secretParams ...string) *upstreamClient {
+	c := &upstreamClient{
+		name:         name,
+		client:       &http.Client{},
+		timeout:      cfg.UpstreamTimeout.Std(),
+		retries:      cfg.UpstreamRetries,
+		backoff:      cfg.UpstreamBackoff.Std(),
+		breaker:      newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown.Std()),
+		quota:        quota,
+		secretParams: secretParams,
+	}
+	c.breaker.onChange = c.breakerChanged
+	return c
+}
+
+// Do executa a requisição, repetindo as tentativas que falharem por motivos transitórios.
+// Erros de rede e o circuito aberto são devolvidos como errUpstreamUnavailable e a cota
+// esgotada como errUpstreamRateLimited; respostas HTTP (inclusive de erro) são devolvidas
+// para que o fornecedor as interprete.
+func (c *upstreamClient) Do(ctx context.Context, req *http.Request) (*upstreamResponse, error) {
+	if !c.breaker.Allow() {
+		c.rejected.Add(1)
+		return nil, fmt.Errorf("%w: circuito aberto para %s", errUpstreamUnavailable, c.name)
+	}
+	c.requests.Add(1)
+
+	var resp *upstreamResponse
+	var err error
+	for attempt := 0; ; attempt++ {
+		if qerr := c.quota.acquire(ctx); qerr != nil {
+			if attempt == 0 || ctx.Err() != nil {
+				c.breaker.Abort()
+				return nil, qerr
+			}
+			// Sem cota para uma nova tentativa: fica o resultado da anterior
+			break
+		}
+
+		c.attempts.Add(1)
+		resp, err = c.attempt(ctx, req)
+		if ctx.Err() != nil {
+			// O cliente desistiu: a falha não diz nada sobre o fornecedor
+			c.breaker.Abort()
+			return nil, ctx.Err()
+		}
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			c.quota.throttle(parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()))
+		}
+		if attempt >= c.retries || !retryable(req, resp, err) {
+			break
+		}
+		wait, ok := c.backoffFor(attempt, resp)
+		if !ok {
+			break
+		}
+		if deadline, has := ctx.Deadline(); has && time.Until(deadline) < wait {
+			// Não há tempo para outra tentativa dentro do prazo do cliente
+			break
+		}
+
+		c.retried.Add(1)
+		log.Printf("Tentativa %d de %s falhou (%s); nova tentativa em %s", attempt+1, c.name, describeAttempt(resp, err), wait.Round(time.Millisecond))
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			c.breaker.Abort()
+			return nil, ctx.Err()
+		}
+	}
+
+	if err != nil || resp.StatusCode >= 500 {
+		c.failures.Add(1)
+		c.breaker.Failure()
+	} else {
+		c.breaker.Success()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: falha na requisição HTTP: %v", errUpstreamUnavailable, err)
+	}
+	return resp, nil
+}
+
+// attempt faz uma única tentativa, com o timeout por tentativa, e lê o corpo da resposta
+func (c *upstreamClient) attempt(ctx context.Context, req *http.Request) (*upstreamResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	resp, err := c.client.Do(req.Clone(ctx))
+	if err != nil {
+		// O erro inclui a URL, que é registrada sem os parâmetros secretos
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = c.redact(req.URL)
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler a resposta: %v", err)
+	}
+	return &upstreamResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// retryable indica se a tentativa pode ser repetida: só requisições idempotentes,
+// e só quando a falha é transitória (rede, timeout ou erro temporário do servidor)
+func retryable(req *http.Request, resp *upstreamResponse, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoffFor calcula a espera antes da próxima tentativa: backoff * 2^attempt, limitado a
+// upstreamMaxBackoff, com jitter entre metade e o valor cheio para espalhar as repetições.
+// Um Retry-After do fornecedor é respeitado; se passar do limite, não há nova tentativa.
+func (c *upstreamClient) backoffFor(attempt int, resp *upstreamResponse) (time.Duration, bool) {
+	d := c.backoff << uint(attempt)
+	if d <= 0 || d > upstreamMaxBackoff {
+		d = upstreamMaxBackoff
+	}
+	if half := int64(d / 2); half > 0 {
+		d = time.Duration(half + rand.Int63n(half+1))
+	}
+
+	if resp != nil {
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); retryAfter > 0 {
+			if retryAfter > upstreamMaxBackoff {
+				return 0, false
+			}
+			if retryAfter > d {
+				d = retryAfter
+			}
+		}
+	}
+	return d, true
+}
+
+// describeAttempt resume o resultado de uma tentativa para o log
+func describeAttempt(resp *upstreamResponse, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode)
+}
+
+// redact retorna a URL com os parâmetros secretos substituídos por REDACTED
+func (c *upstreamClient) redact(u *url.URL) string {
+	redacted := *u
+	params := redacted.Query()
+	for _, name := range c.secretParams {
+		if params.Has(name) {
+			params.Set(name, "REDACTED")
+		}
+	}
+	redacted.RawQuery = params.Encode()
+	return redacted.String()
+}
+
+// breakerChanged registra as mudanças de estado do circuit breaker nos contadores e no log
+func (c *upstreamClient) breakerChanged(from, to breakerState) {
+	switch to {
+	case breakerOpen:
+		c.opened.Add(1)
+	case breakerHalfOpen:
+		c.halfOpened.Add(1)
+	case breakerClosed:
+		c.closed.Add(1)
+	}
+	log.Printf("Circuit breaker de %s: %s -> %s", c.name, from, to)
+}
+
+// Stats retorna uma cópia dos contadores atuais
+func (c *upstreamClient) Stats() upstreamStats {
+	return upstreamStats{
+		Breaker:    c.breaker.State().String(),
+		Requests:   c.requests.Load(),
+		Attempts:   c.attempts.Load(),
+		Retries:    c.retried.Load(),
+		Failures:   c.failures.Load(),
+		Rejected:   c.rejected.Load(),
+		Opened:     c.opened.Load(),
+		HalfOpened: c.halfOpened.Load(),
+		Closed:     c.closed.Load(),
+	}
+}
+
+// upstreamSet reúne os clientes upstream e as cotas criados na inicialização, para o endpoint administrativo
+type upstreamSet struct {
+	mu      sync.Mutex
+	clients []*upstreamClient
+	quotas  map[string]*upstreamQuota
+}
+
+// client cria um cliente upstream e o registra no conjunto
+func (s *upstreamSet) client(name string, cfg *config.GRPCConfig, quota *upstreamQuota, secretParams ...string) *upstreamClient {
+	c := newUpstreamClient(name, cfg, quota, secretParams...)
+	s.mu.Lock()
+	s.clients = append(s.clients, c)
+	s.mu.Unlock()
+	return c
+}
+
+// quota retorna a cota da chave com o nome informado, criando-a na primeira chamada.
+// Clientes que usam a mesma chave de API devem dividir a mesma cota.
+func (s *upstreamSet) quota(name string, perMinute, perDay int, maxWait time.Duration) *upstreamQuota {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if q, ok := s.quotas[name]; ok {
+		return q
+	}
+	if s.quotas == nil {
+		s.quotas = make(map[string]*upstreamQuota)
+	}
+	q := newUpstreamQuota(name, perMinute, perDay, maxWait)
+	s.quotas[name] = q
+	return q
+}
+
+// Stats retorna os contadores de cada cliente, pelo nome
+func (s *upstreamSet) Stats() map[string]upstreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]upstreamStats, len(s.clients))
+	for _, c := range s.clients {
+		out[c.name] = c.Stats()
+	}
+	return out
+}
+
+// QuotaStats retorna a situação de cada cota, pelo nome da chave
+func (s *upstreamSet) QuotaStats() map[string]quotaStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]quotaStatus, len(s.quotas))
+	for name, q := range s.quotas {
+		out[name] = q.Status()
+	}
+	return out
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"syscall/js"
+
+	"grpc-client/wasm/i18n"
 )
 
 // Função que será chamada para renderizar a página Weather
@@ -10,7 +12,7 @@ import (
 // Após gerar o HTML, ele adiciona o evento de submissão para o formulário de busca de clima.
 func renderWeatherPage() {
 	// Define o HTML da página "Weather", incluindo um formulário para inserir o nome da cidade.
-	content := `<h1>Página inicial</h1>`
+	content := `<h1>` + i18n.T("home.title") + `</h1>`
 
 	// Atualiza o conteúdo da div com id "content", substituindo o conteúdo atual pelo novo HTML da página "Weather".
 	document := js.Global().Get("document")
@@ -19,6 +21,9 @@ func renderWeatherPage() {
 }
 
 func main() {
+	// Carrega o idioma escolhido pelo usuário antes de montar a página.
+	i18n.Init()
+
 	// Chama a função para renderizar a página Weather quando o módulo WASM for carregado.
 	renderWeatherPage()
 
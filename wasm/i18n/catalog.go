@@ -0,0 +1,176 @@
+// Package i18n guarda as mensagens da interface nos idiomas suportados pelo frontend
+// e o idioma escolhido pelo usuário, compartilhado pelos módulos WASM.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Idiomas suportados pela interface (tags BCP 47, as mesmas aceitas pelo backend em ?lang=)
+const (
+	PortugueseBR = "pt-BR"
+	English      = "en"
+
+	// Default é usado quando o navegador pede um idioma sem tradução
+	Default = PortugueseBR
+)
+
+// Locales lista os idiomas na ordem exibida no seletor, com o nome de cada um no próprio idioma
+var Locales = []struct{ Tag, Name string }{
+	{PortugueseBR, "Português"},
+	{English, "English"},
+}
+
+// Mensagens da interface por idioma. Mensagens com argumentos usam os verbos de fmt.
+var catalog = map[string]map[string]string{
+	PortugueseBR: {
+		"nav.home":    "Início",
+		"nav.weather": "Clima",
+		"nav.about":   "Sobre",
+		"nav.locale":  "Idioma",
+		"nav.token":   "Token de acesso",
+
+		"home.title":     "Página inicial",
+		"about.title":    "Sobre",
+		"about.text":     "Este é o conteúdo da página sobre nós.",
+		"notFound.title": "404 - Página não encontrada",
+
+		"weather.title":           "Clima",
+		"weather.prompt":          "Insira uma cidade para buscar o clima:",
+		"weather.cityPlaceholder": "Nome da cidade",
+		"weather.search":          "Buscar Clima",
+		"weather.useMyLocation":   "Usar minha localização",
+		"weather.locating":        "Obtendo sua localização...",
+		"weather.geoDenied":       "Permissão de localização negada. Digite o nome da cidade para buscar o clima.",
+		"weather.geoTimeout":      "Tempo esgotado ao obter sua localização. Tente novamente ou digite o nome da cidade.",
+		"weather.geoUnavailable":  "Não foi possível determinar sua localização. Digite o nome da cidade para buscar o clima.",
+		"weather.fetchError":      "Erro ao obter dados de clima",
+		"weather.error":           "Erro: %s",
+		"weather.retryAfter":      "Tente novamente em %d segundos.",
+		"weather.source":          "Fonte: %s",
+		"weather.cached":          "(cache)",
+		"weather.feelsLike":       "Sensação térmica",
+		"weather.minMax":          "Mín / Máx",
+		"weather.humidity":        "Umidade",
+		"weather.pressure":        "Pressão",
+		"weather.wind":            "Vento",
+		"weather.clouds":          "Nuvens",
+		"weather.visibility":      "Visibilidade",
+		"weather.sunrise":         "Nascer do sol",
+		"weather.sunset":          "Pôr do sol",
+		"weather.updatedNow":      "Atualizado agora mesmo",
+		"weather.updatedMinute":   "Atualizado há 1 minuto",
+		"weather.updatedMinutes":  "Atualizado há %d minutos",
+		"weather.updatedHour":     "Atualizado há 1 hora",
+		"weather.updatedHours":    "Atualizado há %d horas",
+		"weather.updatedDays":     "Atualizado há %d dias",
+		"weather.compass":         "N NE L SE S SO O NO",
+		"weather.stale":           "Serviço de clima indisponível: exibindo a última observação conhecida.",
+		"history.title":           "Últimas 24 horas",
+		"history.notEnough":       "Ainda não há observações suficientes desta cidade para o gráfico das últimas 24 horas.",
+		"forecast.fetchError":     "Erro ao obter previsão do tempo",
+		"forecast.day":            "Dia",
+		"forecast.condition":      "Condição",
+		"forecast.min":            "Mín",
+		"forecast.max":            "Máx",
+		"forecast.precipitation":  "Chuva",
+		"forecast.wind":           "Vento",
+		"forecast.humidity":       "Umidade",
+	},
+	English: {
+		"nav.home":    "Home",
+		"nav.weather": "Weather",
+		"nav.about":   "About",
+		"nav.locale":  "Language",
+		"nav.token":   "Access token",
+
+		"home.title":     "Home page",
+		"about.title":    "About",
+		"about.text":     "This is the about us page.",
+		"notFound.title": "404 - Page not found",
+
+		"weather.title":           "Weather",
+		"weather.prompt":          "Enter a city to get the weather:",
+		"weather.cityPlaceholder": "City name",
+		"weather.search":          "Get Weather",
+		"weather.useMyLocation":   "Use my location",
+		"weather.locating":        "Getting your location...",
+		"weather.geoDenied":       "Location permission denied. Type the city name to get the weather.",
+		"weather.geoTimeout":      "Timed out while getting your location. Try again or type the city name.",
+		"weather.geoUnavailable":  "Could not determine your location. Type the city name to get the weather.",
+		"weather.fetchError":      "Failed to get weather data",
+		"weather.error":           "Error: %s",
+		"weather.retryAfter":      "Try again in %d seconds.",
+		"weather.source":          "Source: %s",
+		"weather.cached":          "(cached)",
+		"weather.feelsLike":       "Feels like",
+		"weather.minMax":          "Min / Max",
+		"weather.humidity":        "Humidity",
+		"weather.pressure":        "Pressure",
+		"weather.wind":            "Wind",
+		"weather.clouds":          "Clouds",
+		"weather.visibility":      "Visibility",
+		"weather.sunrise":         "Sunrise",
+		"weather.sunset":          "Sunset",
+		"weather.updatedNow":      "Updated just now",
+		"weather.updatedMinute":   "Updated 1 minute ago",
+		"weather.updatedMinutes":  "Updated %d minutes ago",
+		"weather.updatedHour":     "Updated 1 hour ago",
+		"weather.updatedHours":    "Updated %d hours ago",
+		"weather.updatedDays":     "Updated %d days ago",
+		"weather.compass":         "N NE E SE S SW W NW",
+		"weather.stale":           "Weather service unavailable: showing the last known observation.",
+		"history.title":           "Last 24 hours",
+		"history.notEnough":       "There are not enough observations of this city yet for the last 24 hours chart.",
+		"forecast.fetchError":     "Failed to get the forecast",
+		"forecast.day":            "Day",
+		"forecast.condition":      "Condition",
+		"forecast.min":            "Min",
+		"forecast.max":            "Max",
+		"forecast.precipitation":  "Rain",
+		"forecast.wind":           "Wind",
+		"forecast.humidity":       "Humidity",
+	},
+}
+
+// Idioma atual da interface
+var current = Default
+
+// SetLocale troca o idioma da interface; idiomas sem tradução caem no mais próximo (ver Match)
+func SetLocale(locale string) {
+	current = Match(locale)
+}
+
+// Locale retorna o idioma atual da interface
+func Locale() string {
+	return current
+}
+
+// Match aproxima uma tag do navegador (ex.: "pt-PT", "en-US") do idioma suportado mais próximo
+func Match(tag string) string {
+	tag = strings.ToLower(tag)
+	switch {
+	case strings.HasPrefix(tag, "pt"):
+		return PortugueseBR
+	case strings.HasPrefix(tag, "en"):
+		return English
+	default:
+		return Default
+	}
+}
+
+// T retorna a mensagem no idioma atual, formatada com os argumentos.
+// Sem tradução, usa o idioma padrão e, em último caso, a própria chave.
+func T(key string, args ...interface{}) string {
+	message, ok := catalog[current][key]
+	if !ok {
+		if message, ok = catalog[Default][key]; !ok {
+			message = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(message, args...)
+	}
+	return message
+}
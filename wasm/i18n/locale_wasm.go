@@ -0,0 +1,26 @@
+package i18n
+
+import "syscall/js"
+
+// Chave do localStorage onde o idioma escolhido pelo usuário é guardado
+const storageKey = "weatherLocale"
+
+// Init define o idioma da interface: a escolha salva pelo usuário ou, na primeira visita,
+// o idioma do navegador. Cada módulo WASM chama Init ao carregar.
+func Init() {
+	locale := Default
+	if saved := js.Global().Get("localStorage").Call("getItem", storageKey); saved.Truthy() {
+		locale = saved.String()
+	} else if language := js.Global().Get("navigator").Get("language"); language.Truthy() {
+		locale = language.String()
+	}
+	SetLocale(locale)
+	js.Global().Get("document").Get("documentElement").Set("lang", Locale())
+}
+
+// Save troca o idioma da interface e guarda a escolha para as próximas visitas
+func Save(locale string) {
+	SetLocale(locale)
+	js.Global().Get("localStorage").Call("setItem", storageKey, Locale())
+	js.Global().Get("document").Get("documentElement").Set("lang", Locale())
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"html"
+	"strings"
 	"syscall/js"
+
+	"grpc-client/wasm/i18n"
+	"grpc-client/wasm/session"
 )
 
 // Função para simular navegação sem alterar a URL visível
@@ -19,6 +24,15 @@ func navigate(page string) {
 // Esta função carrega o conteúdo apropriado (HTML) de acordo com a página solicitada.
 // Se a página for "weather", o módulo WebAssembly do clima é carregado dinamicamente.
 func changeContent(page string) {
+	currentPage = page
+
+	// Encerra a página anterior, se ela registrou uma função para isso (ex.: o módulo do clima,
+	// que fecha a assinatura de clima); cada visita carrega uma nova instância do módulo
+	if teardown := js.Global().Get("pageTeardown"); teardown.Type() == js.TypeFunction {
+		js.Global().Delete("pageTeardown")
+		teardown.Invoke()
+	}
+
 	switch page {
 	case "home":
 		// Conteúdo da página "Home"
@@ -28,11 +42,11 @@ func changeContent(page string) {
 		loadWeatherModule()
 	case "about":
 		// Conteúdo da página "About"
-		content := `<h1>SObre</h1><p>Este é o conteúdo da página sobre nós.</p>`
+		content := `<h1>` + i18n.T("about.title") + `</h1><p>` + i18n.T("about.text") + `</p>`
 		setContent(content)
 	default:
 		// Se a página não for encontrada, exibe a página de erro 404
-		content := `<h1>404 - Página não encontrada</h1>`
+		content := `<h1>` + i18n.T("notFound.title") + `</h1>`
 		setContent(content)
 	}
 }
@@ -124,6 +138,65 @@ func loadWeatherModule() {
 	}))
 }
 
+// Página exibida no momento, recarregada quando o usuário troca o idioma
+var currentPage = "home"
+
+// Função que traduz os links da barra de navegação e acrescenta o seletor de idioma
+// Os links vêm do index.html; o seletor é criado aqui com os idiomas do catálogo de mensagens.
+func renderNav() {
+	document := js.Global().Get("document")
+	for id, key := range map[string]string{"nav-home": "nav.home", "nav-weather": "nav.weather", "nav-about": "nav.about"} {
+		document.Call("getElementById", id).Set("textContent", i18n.T(key))
+	}
+
+	localeSelect := document.Call("getElementById", "localeSelect")
+	if !localeSelect.Truthy() {
+		item := document.Call("createElement", "li")
+		item.Set("innerHTML", `<select id="localeSelect"></select>`)
+		document.Call("getElementById", "main-nav").Call("querySelector", "ul").Call("appendChild", item)
+		localeSelect = document.Call("getElementById", "localeSelect")
+		localeSelect.Call("addEventListener", "change", js.FuncOf(changeLocale))
+	}
+
+	options := ""
+	for _, l := range i18n.Locales {
+		options += `<option value="` + l.Tag + `">` + html.EscapeString(l.Name) + `</option>`
+	}
+	localeSelect.Set("innerHTML", options)
+	localeSelect.Set("value", i18n.Locale())
+	localeSelect.Set("title", i18n.T("nav.locale"))
+
+	// Token de acesso enviado ao gateway quando a API exige autenticação
+	tokenInput := document.Call("getElementById", "tokenInput")
+	if !tokenInput.Truthy() {
+		item := document.Call("createElement", "li")
+		item.Set("innerHTML", `<input id="tokenInput" type="password" autocomplete="off" size="12">`)
+		document.Call("getElementById", "main-nav").Call("querySelector", "ul").Call("appendChild", item)
+		tokenInput = document.Call("getElementById", "tokenInput")
+		tokenInput.Set("value", session.Token())
+		tokenInput.Call("addEventListener", "change", js.FuncOf(changeToken))
+	}
+	tokenInput.Set("placeholder", i18n.T("nav.token"))
+	tokenInput.Set("title", i18n.T("nav.token"))
+}
+
+// Função chamada quando o usuário altera o token de acesso na barra de navegação.
+// O token é lido a cada requisição, então a página atual é recarregada para usá-lo.
+func changeToken(this js.Value, p []js.Value) interface{} {
+	session.SaveToken(strings.TrimSpace(this.Get("value").String()))
+	changeContent(currentPage)
+	return nil
+}
+
+// Função chamada quando o usuário troca o idioma no seletor da barra de navegação
+// Guarda a escolha (lida pelos demais módulos WASM ao carregar) e redesenha a navegação e a página atual.
+func changeLocale(this js.Value, p []js.Value) interface{} {
+	i18n.Save(this.Get("value").String())
+	renderNav()
+	changeContent(currentPage)
+	return nil
+}
+
 // Função que escuta mudanças no histórico (evento popstate)
 // Esta função é chamada quando o usuário usa o botão "voltar" ou "avançar" do navegador.
 // Com base no estado armazenado no histórico, ela recarrega a página correta.
@@ -150,6 +223,10 @@ func addEventDelegation() {
 	nav := document.Call("getElementById", "main-nav")
 	nav.Call("addEventListener", "click", js.FuncOf(func(this js.Value, p []js.Value) interface{} {
 		event := p[0]
+		// Só os links navegam; cliques no seletor de idioma seguem o comportamento padrão
+		if event.Get("target").Get("tagName").String() != "A" {
+			return nil
+		}
 		event.Call("preventDefault") // Evita o comportamento padrão de redirecionamento de link
 
 		// Verifica qual link foi clicado (Home, Weather ou About) e navega para a página correta
@@ -167,6 +244,10 @@ func addEventDelegation() {
 }
 
 func main() {
+	// Carrega o idioma escolhido pelo usuário (ou o do navegador) e traduz a navegação
+	i18n.Init()
+	renderNav()
+
 	// Inicializa a delegação de eventos na barra de navegação
 	addEventDelegation()
 
@@ -0,0 +1,49 @@
+// Package session guarda o token de acesso informado pelo usuário e o anexa às
+// requisições dos módulos WASM ao gateway.
+package session
+
+import "syscall/js"
+
+// Chave do localStorage onde o token de acesso é guardado
+const storageKey = "weatherToken"
+
+// Token retorna o token guardado (vazio se o usuário não informou nenhum)
+func Token() string {
+	if saved := js.Global().Get("localStorage").Call("getItem", storageKey); saved.Truthy() {
+		return saved.String()
+	}
+	return ""
+}
+
+// SaveToken guarda o token para as próximas requisições; vazio remove o token
+func SaveToken(token string) {
+	storage := js.Global().Get("localStorage")
+	if token == "" {
+		storage.Call("removeItem", storageKey)
+		return
+	}
+	storage.Call("setItem", storageKey, token)
+}
+
+// Fetch chama fetch com o cabeçalho Authorization: Bearer, quando há token, e retorna a promise
+func Fetch(url string) js.Value {
+	token := Token()
+	if token == "" {
+		return js.Global().Call("fetch", url)
+	}
+	headers := js.Global().Get("Object").New()
+	headers.Set("Authorization", "Bearer "+token)
+	options := js.Global().Get("Object").New()
+	options.Set("headers", headers)
+	return js.Global().Call("fetch", url, options)
+}
+
+// StreamURL acrescenta o token à URL como access_token, para o EventSource,
+// que não permite enviar cabeçalhos
+func StreamURL(url string) string {
+	token := Token()
+	if token == "" {
+		return url
+	}
+	return url + "&access_token=" + js.Global().Call("encodeURIComponent", token).String()
+}
@@ -2,7 +2,13 @@ package main
 
 import (
 	"fmt"
+	"html"
+	"strconv"
+	"strings"
 	"syscall/js"
+
+	"grpc-client/wasm/i18n"
+	"grpc-client/wasm/session"
 )
 
 // Função que será chamada para renderizar a página Weather
@@ -11,12 +17,24 @@ import (
 // Após gerar o HTML, ele adiciona o evento de submissão para o formulário de busca de clima.
 func renderWeatherPage() {
 	// Define o HTML da página "Weather", incluindo um formulário para inserir o nome da cidade.
-	content := `<h1>Weather Page</h1><p>Insira uma cidade para buscar o clima:</p>
+	// Os textos vêm do catálogo de mensagens, no idioma escolhido pelo usuário.
+	content := `<h1>` + i18n.T("weather.title") + `</h1><p>` + i18n.T("weather.prompt") + `</p>
 	<form id="weatherForm" onsubmit="event.preventDefault(); if (typeof getWeather === 'function') getWeather(event);">
-		<input type="text" id="cityInput" placeholder="Nome da cidade"/>
-		<button type="submit">Buscar Clima</button>
+		<span style="position: relative; display: inline-block;">
+			<input type="text" id="cityInput" placeholder="` + i18n.T("weather.cityPlaceholder") + `" autocomplete="off"/>
+			<ul id="citySuggestions" hidden style="position: absolute; left: 0; right: 0; margin: 0; padding: 0; list-style: none; background: #fff; border: 1px solid #ccc; z-index: 10;"></ul>
+		</span>
+		<select id="unitsSelect">
+			<option value="metric">°C</option>
+			<option value="imperial">°F</option>
+			<option value="standard">K</option>
+		</select>
+		<button type="submit">` + i18n.T("weather.search") + `</button>
+		<button type="button" id="locationButton">` + i18n.T("weather.useMyLocation") + `</button>
 	</form>
-	<div id="output"></div>`
+	<div id="output"></div>
+	<div id="history"></div>
+	<div id="forecast"></div>`
 
 	// Atualiza o conteúdo da div com id "content", substituindo o conteúdo atual pelo novo HTML da página "Weather".
 	document := js.Global().Get("document")
@@ -27,12 +45,267 @@ func renderWeatherPage() {
 	// O evento "submit" dispara a função getWeather quando o usuário submeter o formulário.
 	form := document.Call("getElementById", "weatherForm")
 	form.Call("addEventListener", "submit", js.FuncOf(getWeather))
+
+	// Restaura a unidade escolhida na última visita e recarrega o clima quando ela mudar.
+	unitsSelect := document.Call("getElementById", "unitsSelect")
+	if saved := js.Global().Get("localStorage").Call("getItem", unitsStorageKey); saved.Truthy() {
+		unitsSelect.Set("value", saved)
+	}
+	unitsSelect.Call("addEventListener", "change", js.FuncOf(changeUnits))
+
+	// Sugestões de cidades enquanto o usuário digita
+	cityInput := document.Call("getElementById", "cityInput")
+	cityInput.Call("addEventListener", "input", js.FuncOf(cityInputChanged))
+	cityInput.Call("addEventListener", "blur", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		hideSuggestions()
+		return nil
+	}))
+	suggestionsList := document.Call("getElementById", "citySuggestions")
+	suggestionsList.Call("addEventListener", "mousedown", js.FuncOf(chooseSuggestion))
+	searchSuggestions = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		go fetchSuggestions(cityInput.Get("value").String())
+		return nil
+	})
+
+	// Mantém atualizado o "Atualizado há N minutos" do cartão de clima
+	ageTimer = js.Global().Call("setInterval", js.FuncOf(refreshWeatherAges), 60000)
+
+	// Clima da localização atual do navegador. Sem suporte a geolocalização, o botão é escondido.
+	locationButton := document.Call("getElementById", "locationButton")
+	if js.Global().Get("navigator").Get("geolocation").Truthy() {
+		locationButton.Call("addEventListener", "click", js.FuncOf(useMyLocation))
+	} else {
+		locationButton.Set("hidden", true)
+	}
+}
+
+// Opções da geolocalização: aceita uma posição de até 10 minutos atrás e desiste após 10 segundos
+var geolocationOptions = map[string]interface{}{
+	"enableHighAccuracy": false,
+	"timeout":            10000,
+	"maximumAge":         600000,
+}
+
+// Função chamada pelo botão "Usar minha localização"
+// Pede a posição ao navegador (o que pode exibir o pedido de permissão ao usuário) e busca
+// o clima pelas coordenadas; o servidor resolve a cidade e o nome aparece na saída.
+func useMyLocation(this js.Value, p []js.Value) interface{} {
+	updateOutput(i18n.T("weather.locating"))
+
+	var success, failure js.Func
+	release := func() {
+		success.Release()
+		failure.Release()
+	}
+	success = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer release()
+		coords := args[0].Get("coords")
+		// Quatro casas decimais (~11 m) bastam para o clima e evitam expor a posição exata
+		currentLocation = fmt.Sprintf("lat=%.4f&lon=%.4f", coords.Get("latitude").Float(), coords.Get("longitude").Float())
+		selectedCity = nil
+
+		document := js.Global().Get("document")
+		document.Call("getElementById", "cityInput").Set("value", "")
+		hideSuggestions()
+
+		loadWeather(currentLocation)
+		return nil
+	})
+	failure = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer release()
+		updateOutput(geolocationErrorMessage(args[0].Get("code").Int()))
+		return nil
+	})
+
+	js.Global().Get("navigator").Get("geolocation").Call("getCurrentPosition", success, failure, geolocationOptions)
+	return nil
+}
+
+// Função que traduz o código de erro da geolocalização (GeolocationPositionError) para o usuário
+func geolocationErrorMessage(code int) string {
+	switch code {
+	case 1: // PERMISSION_DENIED
+		return i18n.T("weather.geoDenied")
+	case 3: // TIMEOUT
+		return i18n.T("weather.geoTimeout")
+	default: // POSITION_UNAVAILABLE
+		return i18n.T("weather.geoUnavailable")
+	}
+}
+
+// Atraso entre a última tecla digitada e a busca de sugestões, para não consultar o backend a cada letra
+const suggestionDelayMs = 300
+
+// Cidade sugerida pelo backend (rota /cities)
+type citySuggestion struct {
+	label    string // Texto exibido e copiado para o campo (ex.: "São José, Santa Catarina, BR")
+	lat, lon float64
+}
+
+var (
+	// Busca de sugestões agendada (setTimeout), cancelada a cada nova tecla
+	suggestionTimer   js.Value
+	searchSuggestions js.Func
+
+	// Sugestões exibidas na lista e a escolhida pelo usuário, consultada pelas coordenadas
+	suggestions  []citySuggestion
+	selectedCity *citySuggestion
+)
+
+// Função chamada a cada alteração no campo de cidade
+// Reagenda a busca de sugestões para depois que o usuário parar de digitar.
+func cityInputChanged(this js.Value, p []js.Value) interface{} {
+	// O texto mudou: a cidade escolhida na lista deixa de valer
+	selectedCity = nil
+
+	if suggestionTimer.Truthy() {
+		js.Global().Call("clearTimeout", suggestionTimer)
+	}
+	if len([]rune(strings.TrimSpace(this.Get("value").String()))) < 2 {
+		hideSuggestions()
+		return nil
+	}
+	suggestionTimer = js.Global().Call("setTimeout", searchSuggestions, suggestionDelayMs)
+	return nil
+}
+
+// Função para buscar as sugestões de cidades no backend e exibi-las abaixo do campo
+func fetchSuggestions(text string) {
+	url := "/cities?limit=5&q=" + js.Global().Call("encodeURIComponent", text).String()
+
+	fetchJSON(url, func(json js.Value) {
+		// Ignora respostas de buscas antigas se o usuário continuou digitando
+		document := js.Global().Get("document")
+		if document.Call("getElementById", "cityInput").Get("value").String() != text {
+			return
+		}
+		cities := json.Get("cities")
+		if !cities.Truthy() {
+			hideSuggestions()
+			return
+		}
+		renderSuggestions(cities)
+	}, hideSuggestions)
+}
+
+// Função que monta a lista de sugestões a partir das cidades retornadas pelo backend
+func renderSuggestions(cities js.Value) {
+	suggestions = suggestions[:0]
+	var b strings.Builder
+	for i := 0; i < cities.Length(); i++ {
+		c := cities.Index(i)
+		parts := []string{c.Get("name").String()}
+		if region := c.Get("region"); region.Truthy() {
+			parts = append(parts, region.String())
+		}
+		parts = append(parts, c.Get("country").String())
+
+		suggestion := citySuggestion{
+			label: strings.Join(parts, ", "),
+			lat:   c.Get("coordinates").Get("lat").Float(),
+			lon:   c.Get("coordinates").Get("lon").Float(),
+		}
+		suggestions = append(suggestions, suggestion)
+		b.WriteString(fmt.Sprintf(`<li data-index="%d" style="padding: 4px 8px; cursor: pointer;">%s</li>`, i, html.EscapeString(suggestion.label)))
+	}
+
+	document := js.Global().Get("document")
+	list := document.Call("getElementById", "citySuggestions")
+	list.Set("innerHTML", b.String())
+	list.Set("hidden", len(suggestions) == 0)
+}
+
+// Função chamada ao clicar em uma sugestão
+// Usa "mousedown" porque ele dispara antes do "blur" do campo, que esconde a lista.
+// Preenche o campo com a cidade escolhida e busca o clima pelas coordenadas, sem ambiguidade.
+func chooseSuggestion(this js.Value, p []js.Value) interface{} {
+	index := p[0].Get("target").Call("getAttribute", "data-index")
+	if !index.Truthy() {
+		return nil
+	}
+	i, err := strconv.Atoi(index.String())
+	if err != nil || i < 0 || i >= len(suggestions) {
+		return nil
+	}
+
+	choice := suggestions[i]
+	selectedCity = &choice
+
+	document := js.Global().Get("document")
+	document.Call("getElementById", "cityInput").Set("value", choice.label)
+	hideSuggestions()
+
+	currentLocation = locationQuery(choice.label)
+	loadWeather(currentLocation)
+	return nil
+}
+
+// Função para esconder a lista de sugestões
+func hideSuggestions() {
+	document := js.Global().Get("document")
+	document.Call("getElementById", "citySuggestions").Set("hidden", true)
+}
+
+// Função que monta a parte da query string que identifica a cidade digitada.
+// Se ela foi escolhida na lista de sugestões, usa as coordenadas; caso contrário, o nome.
+func locationQuery(city string) string {
+	if selectedCity != nil && selectedCity.label == city {
+		return fmt.Sprintf("lat=%g&lon=%g", selectedCity.lat, selectedCity.lon)
+	}
+	return "city=" + js.Global().Call("encodeURIComponent", city).String()
+}
+
+// Chave do localStorage onde a unidade escolhida pelo usuário é guardada
+const unitsStorageKey = "weatherUnits"
+
+// Local exibido no momento (ex.: "city=Recife"), recarregado quando o usuário troca a unidade
+var currentLocation string
+
+// Função chamada quando o usuário troca a unidade de medida
+// Guarda a escolha no localStorage e, se houver uma cidade na tela, busca o clima novamente na nova unidade.
+func changeUnits(this js.Value, p []js.Value) interface{} {
+	js.Global().Get("localStorage").Call("setItem", unitsStorageKey, selectedUnits())
+	if currentLocation != "" {
+		loadWeather(currentLocation)
+	}
+	return nil
+}
+
+// Função que retorna a unidade selecionada ("metric", "imperial" ou "standard")
+func selectedUnits() string {
+	document := js.Global().Get("document")
+	return document.Call("getElementById", "unitsSelect").Get("value").String()
+}
+
+// Função que monta a query string com o local, a unidade selecionada e o idioma da interface,
+// para que o backend devolva as descrições do clima no mesmo idioma
+func weatherQuery(location string) string {
+	return location + "&units=" + selectedUnits() + "&lang=" + i18n.Locale()
+}
+
+// Símbolos de temperatura e velocidade do vento para a unidade informada pelo backend
+func temperatureSymbol(units string) string {
+	switch units {
+	case "imperial":
+		return "°F"
+	case "standard":
+		return "K"
+	default:
+		return "°C"
+	}
+}
+
+func speedSymbol(units string) string {
+	if units == "imperial" {
+		return "mph"
+	}
+	return "m/s"
 }
 
 // Função para buscar o clima no backend
 // Essa função é disparada quando o formulário é submetido.
 // Ela evita o comportamento padrão de reload da página e obtém o valor inserido pelo usuário (nome da cidade).
-// Em seguida, chama a função fetchWeather para fazer a requisição ao backend com o nome da cidade.
+// Em seguida, assina as atualizações de clima da cidade (ou chama fetchWeather, se o navegador não suportar EventSource).
 func getWeather(this js.Value, p []js.Value) interface{} {
 	event := p[0]
 	event.Call("preventDefault") // Evita o comportamento padrão de reload da página ao submeter o formulário.
@@ -40,46 +313,279 @@ func getWeather(this js.Value, p []js.Value) interface{} {
 	// Obtém o valor digitado no campo de input (nome da cidade).
 	document := js.Global().Get("document")
 	city := document.Call("getElementById", "cityInput").Get("value").String()
+	currentLocation = locationQuery(city)
+	hideSuggestions()
 
-	// Inicia uma requisição ao backend de forma assíncrona para buscar o clima da cidade inserida.
-	go fetchWeather(city)
-
+	loadWeather(currentLocation)
 	return nil
 }
 
+// Função que carrega o clima atual e a previsão do local na unidade selecionada
+func loadWeather(location string) {
+	// Acompanha o clima da cidade em tempo real. Sem suporte a EventSource,
+	// faz uma única requisição ao backend de forma assíncrona.
+	if js.Global().Get("EventSource").Truthy() {
+		subscribeWeather(location)
+	} else {
+		go fetchWeather(location)
+	}
+
+	// Busca também a previsão dos próximos dias para a mesma cidade.
+	go fetchForecast(location)
+}
+
 // Função para realizar a requisição HTTP ao backend
 // Essa função constrói a URL para o backend usando o nome da cidade inserida pelo usuário.
 // Faz uma requisição HTTP utilizando "fetch" e processa a resposta com promises para obter os dados do clima.
 // A função atualiza a interface com os dados da cidade, temperatura e descrição do clima ou exibe uma mensagem de erro caso a requisição falhe.
-func fetchWeather(city string) {
-	// Constroi a URL da API do backend para buscar o clima da cidade inserida.
-	url := "/weather?city=" + city
-
-	// Realiza a requisição HTTP ao backend usando fetch.
-	fetch := js.Global().Call("fetch", url)
-
-	// Processa a resposta do servidor com promises. Primeiro, obtemos a resposta no formato JSON.
-	fetch.Call("then", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		resp := args[0]
-		return resp.Call("json")
-	})).Call("then", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		// Decodifica o JSON e extrai os dados do clima (nome da cidade, descrição e temperatura).
-		json := args[0]
-		cityName := json.Get("city").String()           // Nome da cidade
-		description := json.Get("description").String() // Descrição do clima (ex.: "nublado")
-		temperature := json.Get("temperature").Float()  // Temperatura em graus Celsius
-
-		// Formata as informações do clima para exibição na interface.
-		weatherInfo := "Cidade: " + cityName + "\nTemperatura: " + fmt.Sprintf("%.2f", temperature) + "°C\nDescrição: " + description
+func fetchWeather(location string) {
+	// Constroi a URL da API do backend para buscar o clima da cidade inserida na unidade selecionada.
+	url := "/weather?" + weatherQuery(location)
+
+	// Realiza a requisição HTTP ao backend e processa a resposta já decodificada do JSON.
+	fetchJSON(url, func(json js.Value) {
+		// Erros do backend chegam como {"error": {...}}; exibe a mensagem enviada pelo servidor.
+		if json.Get("error").Truthy() {
+			updateOutput(errorMessage(json))
+			return
+		}
 
 		// Atualiza a interface exibindo as informações de clima.
-		updateOutput(weatherInfo)
-		return nil
-	})).Call("catch", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		updateWeatherCard(renderWeatherCard(json))
+		go fetchHistory(json.Get("city").String(), json.Get("country").String())
+	}, func() {
 		// Caso haja um erro na requisição, exibe uma mensagem de erro.
-		updateOutput("Erro ao obter dados de clima")
+		updateOutput(i18n.T("weather.fetchError"))
+	})
+}
+
+// Função que busca uma URL do backend e entrega o corpo JSON a onJSON, ou chama onError se a
+// requisição ou a decodificação falharem. As funções JS da promise são liberadas quando ela termina,
+// já que esta função é chamada a cada mensagem do stream de clima.
+func fetchJSON(url string, onJSON func(json js.Value), onError func()) {
+	var decode, handle, fail js.Func
+	release := func() {
+		decode.Release()
+		handle.Release()
+		fail.Release()
+	}
+	decode = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		return args[0].Call("json")
+	})
+	handle = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer release()
+		onJSON(args[0])
 		return nil
-	}))
+	})
+	fail = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		defer release()
+		onError()
+		return nil
+	})
+
+	session.Fetch(url).Call("then", decode).Call("then", handle).Call("catch", fail)
+}
+
+// Assinatura de clima ativa (EventSource), fechada ao buscar outra cidade ou sair da página,
+// e as funções JS dos seus eventos, liberadas junto com ela
+var (
+	weatherEvents     js.Value
+	weatherEventFuncs []js.Func
+)
+
+// Função que encerra a assinatura de clima ativa, se houver, e libera as funções dos seus eventos
+func closeWeatherEvents() {
+	if weatherEvents.Truthy() {
+		weatherEvents.Call("close")
+		weatherEvents = js.Null()
+	}
+	for _, f := range weatherEventFuncs {
+		f.Release()
+	}
+	weatherEventFuncs = nil
+}
+
+// Função para acompanhar o clima em tempo real
+// Abre uma conexão Server-Sent Events com a rota /weather/stream. O servidor envia o clima atual
+// logo após conectar e uma nova mensagem sempre que ele mudar, atualizando o "output" sem polling.
+func subscribeWeather(location string) {
+	// Encerra a assinatura da cidade anterior, se houver
+	closeWeatherEvents()
+
+	url := "/weather/stream?" + weatherQuery(location)
+	weatherEvents = js.Global().Get("EventSource").New(session.StreamURL(url))
+
+	onMessage := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := js.Global().Get("JSON").Call("parse", args[0].Get("data"))
+		updateWeatherCard(renderWeatherCard(data))
+		// Cada nova observação também entra no histórico: o gráfico é atualizado junto
+		go fetchHistory(data.Get("city").String(), data.Get("country").String())
+		return nil
+	})
+
+	// Erro enviado pelo servidor depois que o stream já começou
+	onWeatherError := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := js.Global().Get("JSON").Call("parse", args[0].Get("data"))
+		this.Call("close")
+		updateOutput(errorMessage(data))
+		return nil
+	})
+
+	// O EventSource reconecta sozinho; só tratamos a conexão encerrada de vez.
+	// Isso acontece quando o gateway recusa a assinatura (ex.: cidade inexistente), e o
+	// EventSource não expõe o corpo da resposta: uma requisição comum obtém a mensagem de erro.
+	onError := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if this.Get("readyState").Int() == 2 { // EventSource.CLOSED
+			go fetchWeather(location)
+		}
+		return nil
+	})
+
+	weatherEvents.Set("onmessage", onMessage)
+	weatherEvents.Call("addEventListener", "weather-error", onWeatherError)
+	weatherEvents.Set("onerror", onError)
+	weatherEventFuncs = []js.Func{onMessage, onWeatherError, onError}
+}
+
+// Função que monta o cartão com os dados de clima recebidos do backend
+// O cabeçalho traz o ícone da condição, a cidade, a temperatura e a descrição;
+// abaixo, uma tabela com os demais detalhes da observação.
+func renderWeatherCard(json js.Value) string {
+	// Extrai os dados principais do clima (nome da cidade, descrição e temperatura).
+	cityName := json.Get("city").String() // Nome canônico da cidade resolvida pelo servidor
+	if country := json.Get("country"); country.Truthy() {
+		cityName += ", " + country.String()
+	}
+	description := json.Get("description").String() // Descrição do clima (ex.: "nublado")
+	temperature := json.Get("temperature").Float()  // Temperatura na unidade informada em "units"
+	units := json.Get("units").String()
+	temp, speed := temperatureSymbol(units), speedSymbol(units)
+
+	// Dados desatualizados (fornecedor fora do ar) ganham borda e aviso em destaque
+	stale := json.Get("stale").Bool()
+	class, border := "weather-card", "#ccc"
+	if stale {
+		class, border = "weather-card weather-stale", "#f59e0b"
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(`<div class="%s" style="display: inline-block; padding: 12px; border: 2px solid %s; border-radius: 8px;">`, class, border))
+	if stale {
+		b.WriteString(`<p class="weather-stale-notice" style="margin: 0 0 8px; color: #b45309; font-weight: bold;">⚠ ` +
+			i18n.T("weather.stale") + `</p>`)
+	}
+	b.WriteString(`<div style="display: flex; align-items: center; gap: 8px;">`)
+	if icon := json.Get("icon"); icon.Truthy() {
+		b.WriteString(fmt.Sprintf(`<img src="https://openweathermap.org/img/wn/%s@2x.png" alt="%s" width="64" height="64"/>`,
+			js.Global().Call("encodeURIComponent", icon).String(), html.EscapeString(description)))
+	}
+	b.WriteString(fmt.Sprintf(`<div><strong>%s</strong><br/><span style="font-size: 2em;">%.1f%s</span><br/>%s</div></div>`,
+		html.EscapeString(cityName), temperature, temp, html.EscapeString(description)))
+
+	// Idade dos dados, atualizada a cada minuto por refreshWeatherAges. Sem o horário
+	// da observação, usa o momento em que o servidor consultou o fornecedor.
+	observedAt := json.Get("observedAt")
+	if !observedAt.Truthy() {
+		observedAt = json.Get("fetchedAt")
+	}
+	if observedAt.Truthy() {
+		source := i18n.T("weather.source", json.Get("source").String())
+		if json.Get("cached").Bool() {
+			source += " " + i18n.T("weather.cached")
+		}
+		b.WriteString(fmt.Sprintf(`<p class="weather-age" data-observed-at="%s" title="%s">%s</p>`,
+			html.EscapeString(observedAt.String()), html.EscapeString(source), weatherAge(observedAt.String())))
+	}
+
+	rows := [][2]string{
+		{i18n.T("weather.feelsLike"), fmt.Sprintf("%.1f%s", json.Get("feelsLike").Float(), temp)},
+		{i18n.T("weather.minMax"), fmt.Sprintf("%.1f%s / %.1f%s", json.Get("tempMin").Float(), temp, json.Get("tempMax").Float(), temp)},
+		{i18n.T("weather.humidity"), fmt.Sprintf("%d%%", json.Get("humidity").Int())},
+		{i18n.T("weather.pressure"), fmt.Sprintf("%d hPa", json.Get("pressure").Int())},
+		{i18n.T("weather.wind"), fmt.Sprintf("%.1f %s %s", json.Get("windSpeed").Float(), speed, compassDirection(json.Get("windDirection").Int()))},
+		{i18n.T("weather.clouds"), fmt.Sprintf("%d%%", json.Get("cloudCover").Int())},
+		{i18n.T("weather.visibility"), fmt.Sprintf("%.1f km", json.Get("visibility").Float()/1000)},
+		{i18n.T("weather.sunrise"), clockTime(json.Get("sunrise"))},
+		{i18n.T("weather.sunset"), clockTime(json.Get("sunset"))},
+	}
+	b.WriteString(`<table class="weather-details">`)
+	for _, row := range rows {
+		b.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td></tr>", row[0], html.EscapeString(row[1])))
+	}
+	b.WriteString("</table></div>")
+	return b.String()
+}
+
+// Função que descreve há quanto tempo os dados foram observados (ex.: "Atualizado há 5 minutos")
+func weatherAge(observedAt string) string {
+	date := js.Global().Get("Date")
+	minutes := int((date.Call("now").Float() - date.Call("parse", observedAt).Float()) / 60000)
+	switch {
+	case minutes < 1:
+		return i18n.T("weather.updatedNow")
+	case minutes == 1:
+		return i18n.T("weather.updatedMinute")
+	case minutes < 60:
+		return i18n.T("weather.updatedMinutes", minutes)
+	case minutes < 120:
+		return i18n.T("weather.updatedHour")
+	case minutes < 48*60:
+		return i18n.T("weather.updatedHours", minutes/60)
+	default:
+		return i18n.T("weather.updatedDays", minutes/(24*60))
+	}
+}
+
+// Função que recalcula a idade exibida nos cartões de clima
+// Chamada a cada minuto, para o texto não ficar parado enquanto o clima não muda.
+func refreshWeatherAges(this js.Value, p []js.Value) interface{} {
+	document := js.Global().Get("document")
+	ages := document.Call("querySelectorAll", ".weather-age")
+	for i := 0; i < ages.Length(); i++ {
+		el := ages.Index(i)
+		el.Set("textContent", weatherAge(el.Call("getAttribute", "data-observed-at").String()))
+	}
+	return nil
+}
+
+// Função que converte a direção do vento em graus para o ponto cardeal ou colateral mais próximo
+func compassDirection(degrees int) string {
+	points := strings.Fields(i18n.T("weather.compass"))
+	return points[((degrees%360+360)%360+22)/45%8]
+}
+
+// Função que extrai o horário (HH:MM) de um instante em RFC 3339 enviado no fuso da cidade
+func clockTime(value js.Value) string {
+	s := value.String()
+	if !value.Truthy() || len(s) < 16 {
+		return "-"
+	}
+	return s[11:16]
+}
+
+// Função que extrai a mensagem de um erro do backend ({"error": {"message": ..., "fields": [...]}})
+// Campos inválidos são listados junto da mensagem e, quando houver, o tempo sugerido para tentar novamente.
+func errorMessage(json js.Value) string {
+	body := json.Get("error")
+	message := i18n.T("weather.error", body.Get("message").String())
+
+	fields := body.Get("fields")
+	if fields.Truthy() {
+		for i := 0; i < fields.Length(); i++ {
+			message += "\n- " + fields.Index(i).Get("field").String() + ": " + fields.Index(i).Get("description").String()
+		}
+	}
+	if retry := body.Get("retryAfter"); retry.Truthy() {
+		message += "\n" + i18n.T("weather.retryAfter", retry.Int())
+	}
+	return message
+}
+
+// Função para exibir o cartão de clima na div "output"
+func updateWeatherCard(content string) {
+	document := js.Global().Get("document")
+	output := document.Call("getElementById", "output")
+	output.Set("innerHTML", content)
 }
 
 // Função para atualizar a saída do clima
@@ -91,10 +597,172 @@ func updateOutput(data string) {
 	output.Set("innerText", data) // Define o texto exibido no elemento de saída (div com id "output").
 }
 
+// Função para buscar a previsão de vários dias no backend
+// Faz a requisição para a rota /forecast e renderiza a tabela diária e o gráfico horário na div "forecast".
+func fetchForecast(location string) {
+	url := "/forecast?" + weatherQuery(location)
+
+	fetchJSON(url, func(json js.Value) {
+		if json.Get("error").Truthy() {
+			updateForecast("<p>" + html.EscapeString(errorMessage(json)) + "</p>")
+			return
+		}
+		units := json.Get("units").String()
+		content := renderTemperatureChart(json.Get("hourly"), units, "forecast-chart") + renderForecastTable(json.Get("daily"), units)
+		updateForecast(content)
+	}, func() {
+		updateForecast("<p>" + i18n.T("forecast.fetchError") + "</p>")
+	})
+}
+
+// Função que monta a tabela com o resumo diário da previsão
+// Cada linha mostra a data, a condição, mínima/máxima, chance de chuva, vento e umidade.
+func renderForecastTable(daily js.Value, units string) string {
+	temp, speed := temperatureSymbol(units), speedSymbol(units)
+
+	var b strings.Builder
+	b.WriteString(`<table class="forecast-table"><thead><tr>`)
+	for _, key := range []string{"day", "condition", "min", "max", "precipitation", "wind", "humidity"} {
+		b.WriteString("<th>" + i18n.T("forecast."+key) + "</th>")
+	}
+	b.WriteString(`</tr></thead><tbody>`)
+
+	for i := 0; i < daily.Length(); i++ {
+		day := daily.Index(i)
+		date := js.Global().Get("Date").New(day.Get("time").String())
+		label := date.Call("toLocaleDateString", i18n.Locale(), map[string]interface{}{
+			"weekday": "short", "day": "2-digit", "month": "2-digit", "timeZone": "UTC",
+		}).String()
+
+		b.WriteString(fmt.Sprintf(
+			"<tr><td>%s</td><td>%s</td><td>%.1f%s</td><td>%.1f%s</td><td>%.0f%%</td><td>%.1f %s</td><td>%d%%</td></tr>",
+			html.EscapeString(label),
+			html.EscapeString(day.Get("description").String()),
+			day.Get("tempMin").Float(), temp,
+			day.Get("tempMax").Float(), temp,
+			day.Get("precipitationProbability").Float()*100,
+			day.Get("windSpeed").Float(), speed,
+			day.Get("humidity").Int(),
+		))
+	}
+
+	b.WriteString("</tbody></table>")
+	return b.String()
+}
+
+// Função que desenha o gráfico de temperatura em SVG, usado pela previsão horária e pelo histórico
+// Os pontos são distribuídos igualmente no eixo X e a temperatura é escalada entre a mínima e a máxima do período.
+func renderTemperatureChart(series js.Value, units, class string) string {
+	n := series.Length()
+	if n < 2 {
+		return ""
+	}
+
+	const width, height, padding = 600.0, 150.0, 20.0
+	symbol := temperatureSymbol(units)
+
+	temps := make([]float64, n)
+	minTemp, maxTemp := series.Index(0).Get("temperature").Float(), series.Index(0).Get("temperature").Float()
+	for i := 0; i < n; i++ {
+		temps[i] = series.Index(i).Get("temperature").Float()
+		if temps[i] < minTemp {
+			minTemp = temps[i]
+		}
+		if temps[i] > maxTemp {
+			maxTemp = temps[i]
+		}
+	}
+	span := maxTemp - minTemp
+	if span == 0 {
+		span = 1
+	}
+
+	points := make([]string, n)
+	for i, t := range temps {
+		x := padding + float64(i)*(width-2*padding)/float64(n-1)
+		y := height - padding - (t-minTemp)/span*(height-2*padding)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+
+	return fmt.Sprintf(`<svg class="%s" viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f">`+
+		`<polyline fill="none" stroke="#f59e0b" stroke-width="2" points="%s"/>`+
+		`<text x="2" y="%.0f" font-size="10">%.1f%s</text>`+
+		`<text x="2" y="%.0f" font-size="10">%.1f%s</text>`+
+		`</svg>`,
+		class, width, height, width, height, strings.Join(points, " "),
+		padding, maxTemp, symbol, height-padding/2, minTemp, symbol)
+}
+
+// Função para buscar as observações das últimas 24 horas da cidade exibida
+// Usa o nome canônico devolvido pelo servidor (com o país, para não haver ambiguidade)
+// e desenha a temperatura hora a hora na div "history".
+func fetchHistory(city, country string) {
+	url := "/weather/history?granularity=hourly&units=" + selectedUnits() +
+		"&city=" + js.Global().Call("encodeURIComponent", city).String()
+	if country != "" {
+		url += "&country=" + js.Global().Call("encodeURIComponent", country).String()
+	}
+
+	fetchJSON(url, func(json js.Value) {
+		if json.Get("error").Truthy() {
+			updateHistory("")
+			return
+		}
+		points := json.Get("points")
+		if points.Length() < 2 {
+			updateHistory("<p>" + i18n.T("history.notEnough") + "</p>")
+			return
+		}
+		updateHistory("<h3>" + i18n.T("history.title") + "</h3>" +
+			renderTemperatureChart(points, json.Get("units").String(), "history-chart"))
+	}, func() {
+		updateHistory("")
+	})
+}
+
+// Função para atualizar a área do histórico (div com id "history")
+func updateHistory(content string) {
+	document := js.Global().Get("document")
+	document.Call("getElementById", "history").Set("innerHTML", content)
+}
+
+// Função para atualizar a área de previsão (div com id "forecast")
+func updateForecast(content string) {
+	document := js.Global().Get("document")
+	forecast := document.Call("getElementById", "forecast")
+	forecast.Set("innerHTML", content)
+}
+
 func main() {
+	// Carrega o idioma escolhido pelo usuário antes de montar a página.
+	i18n.Init()
+
 	// Chama a função para renderizar a página Weather quando o módulo WASM for carregado.
 	renderWeatherPage()
 
-	// Mantém o WebAssembly rodando indefinidamente. Sem isso, o módulo seria finalizado imediatamente após carregar a página.
-	select {}
+	// O módulo principal chama window.pageTeardown antes de trocar de página
+	js.Global().Set("pageTeardown", js.FuncOf(teardown))
+
+	// Mantém o WebAssembly rodando até o usuário sair da página. Sem isso, o módulo seria finalizado
+	// imediatamente após carregar a página.
+	<-pageClosed
+}
+
+// Timer de refreshWeatherAges (setInterval), cancelado ao sair da página
+var ageTimer js.Value
+
+// Fechado por teardown para encerrar esta instância do módulo
+var pageClosed = make(chan struct{})
+
+// Função chamada pelo módulo principal quando o usuário sai da página de clima
+// Cada visita carrega uma nova instância deste módulo: a anterior fecha a assinatura de clima
+// (que mantém um stream gRPC aberto no servidor), cancela os seus timers e termina.
+func teardown(this js.Value, p []js.Value) interface{} {
+	closeWeatherEvents()
+	js.Global().Call("clearInterval", ageTimer)
+	if suggestionTimer.Truthy() {
+		js.Global().Call("clearTimeout", suggestionTimer)
+	}
+	close(pageClosed)
+	return nil
 }
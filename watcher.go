@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// weatherWatcher acompanha as cidades com assinaturas ativas.
+// Como os fornecedores não enviam notificações, cada cidade observada é consultada
+// periodicamente e os assinantes só recebem uma mensagem quando o clima muda.
+type weatherWatcher struct {
+	provider WeatherProvider
+	interval time.Duration
+
+	mu      sync.Mutex
+	watches map[string]*cityWatch
+}
+
+// cityWatch guarda o estado de uma cidade observada
+type cityWatch struct {
+	query       WeatherQuery
+	last        *Weather
+	subscribers map[chan *Weather]struct{}
+	stop        context.CancelFunc
+}
+
+func newWeatherWatcher(provider WeatherProvider, interval time.Duration) *weatherWatcher {
+	return &weatherWatcher{
+		provider: provider,
+		interval: interval,
+		watches:  make(map[string]*cityWatch),
+	}
+}
+
+// Subscribe registra um assinante para a cidade e retorna o canal de atualizações
+// e a função que cancela a assinatura. O primeiro assinante de uma cidade inicia
+// a consulta periódica; o último a sair a encerra.
+func (w *weatherWatcher) Subscribe(q WeatherQuery) (<-chan *Weather, func()) {
+	key := q.key()
+	ch := make(chan *Weather, 1)
+
+	w.mu.Lock()
+	watch, ok := w.watches[key]
+	if !ok {
+		ctx, stop := context.WithCancel(context.Background())
+		watch = &cityWatch{
+			query:       q,
+			subscribers: make(map[chan *Weather]struct{}),
+			stop:        stop,
+		}
+		w.watches[key] = watch
+		go w.poll(ctx, key, watch)
+	}
+	watch.subscribers[ch] = struct{}{}
+	// Quem chega depois recebe imediatamente o último clima conhecido
+	if watch.last != nil {
+		ch <- watch.last
+	}
+	w.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			w.mu.Lock()
+			defer w.mu.Unlock()
+			delete(watch.subscribers, ch)
+			if len(watch.subscribers) == 0 {
+				watch.stop()
+				delete(w.watches, key)
+			}
+		})
+	}
+	return ch, cancel
+}
+
+// poll consulta o fornecedor no intervalo configurado até a cidade deixar de ser observada
+func (w *weatherWatcher) poll(ctx context.Context, key string, watch *cityWatch) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		weather, err := w.provider.CurrentWeather(ctx, watch.query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Falha ao atualizar clima observado de %s: %v", watch.query, err)
+		} else {
+			w.publish(watch, weather)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish envia o clima aos assinantes se ele mudou desde a última consulta.
+// Assinantes lentos não bloqueiam os demais: a atualização pendente é substituída pela mais recente.
+func (w *weatherWatcher) publish(watch *cityWatch, weather *Weather) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if watch.last != nil && sameWeather(watch.last, weather) {
+		return
+	}
+	watch.last = weather
+
+	for ch := range watch.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- weather
+	}
+}
+
+// sameWeather indica se duas observações são equivalentes para os assinantes.
+// Compara os valores exibidos ao usuário; nascer e pôr do sol só mudam de um dia para o outro.
+// Uma nova observação do fornecedor também é enviada, para o cliente saber que os dados estão atualizados,
+// assim como a passagem de dados atuais para a última observação conhecida (e vice-versa).
+func sameWeather(a, b *Weather) bool {
+	return a.ObservedAt.Equal(b.ObservedAt) &&
+		a.Stale == b.Stale &&
+		a.Description == b.Description &&
+		a.Temperature == b.Temperature &&
+		a.FeelsLike == b.FeelsLike &&
+		a.TempMin == b.TempMin &&
+		a.TempMax == b.TempMax &&
+		a.Humidity == b.Humidity &&
+		a.Pressure == b.Pressure &&
+		a.WindSpeed == b.WindSpeed &&
+		a.WindDirection == b.WindDirection &&
+		a.CloudCover == b.CloudCover &&
+		a.Visibility == b.Visibility &&
+		a.Icon == b.Icon
+}
@@ -20,31 +20,1469 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Unidade de medida das temperaturas (e do vento) nas respostas
+type Units int32
+
+const (
+	Units_UNITS_UNSPECIFIED Units = 0 // Usa o padrão do servidor (métrico)
+	Units_UNITS_METRIC      Units = 1 // °C e m/s
+	Units_UNITS_IMPERIAL    Units = 2 // °F e mph
+	Units_UNITS_STANDARD    Units = 3 // Kelvin e m/s
+)
+
+// Enum value maps for Units.
+var (
+	Units_name = map[int32]string{
+		0: "UNITS_UNSPECIFIED",
+		1: "UNITS_METRIC",
+		2: "UNITS_IMPERIAL",
+		3: "UNITS_STANDARD",
+	}
+	Units_value = map[string]int32{
+		"UNITS_UNSPECIFIED": 0,
+		"UNITS_METRIC":      1,
+		"UNITS_IMPERIAL":    2,
+		"UNITS_STANDARD":    3,
+	}
+)
+
+func (x Units) Enum() *Units {
+	p := new(Units)
+	*p = x
+	return p
+}
+
+func (x Units) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Units) Descriptor() protoreflect.EnumDescriptor {
+	return file_weather_service_proto_enumTypes[0].Descriptor()
+}
+
+func (Units) Type() protoreflect.EnumType {
+	return &file_weather_service_proto_enumTypes[0]
+}
+
+func (x Units) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Units.Descriptor instead.
+func (Units) EnumDescriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{0}
+}
+
+// Agrupamento das observações do histórico
+type Granularity int32
+
+const (
+	Granularity_GRANULARITY_UNSPECIFIED Granularity = 0 // Usa o padrão do servidor (observações individuais)
+	Granularity_GRANULARITY_RAW         Granularity = 1 // Cada observação registrada
+	Granularity_GRANULARITY_HOURLY      Granularity = 2 // Um ponto por hora
+	Granularity_GRANULARITY_DAILY       Granularity = 3 // Um ponto por dia, no fuso horário da cidade
+)
+
+// Enum value maps for Granularity.
+var (
+	Granularity_name = map[int32]string{
+		0: "GRANULARITY_UNSPECIFIED",
+		1: "GRANULARITY_RAW",
+		2: "GRANULARITY_HOURLY",
+		3: "GRANULARITY_DAILY",
+	}
+	Granularity_value = map[string]int32{
+		"GRANULARITY_UNSPECIFIED": 0,
+		"GRANULARITY_RAW":         1,
+		"GRANULARITY_HOURLY":      2,
+		"GRANULARITY_DAILY":       3,
+	}
+)
+
+func (x Granularity) Enum() *Granularity {
+	p := new(Granularity)
+	*p = x
+	return p
+}
+
+func (x Granularity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Granularity) Descriptor() protoreflect.EnumDescriptor {
+	return file_weather_service_proto_enumTypes[1].Descriptor()
+}
+
+func (Granularity) Type() protoreflect.EnumType {
+	return &file_weather_service_proto_enumTypes[1]
+}
+
+func (x Granularity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Granularity.Descriptor instead.
+func (Granularity) EnumDescriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{1}
+}
+
+// Coordenadas geográficas em graus decimais
+type Coordinates struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Lat float64 `protobuf:"fixed64,1,opt,name=lat,proto3" json:"lat,omitempty"` // -90 a 90
+	Lon float64 `protobuf:"fixed64,2,opt,name=lon,proto3" json:"lon,omitempty"` // -180 a 180
+}
+
+func (x *Coordinates) Reset() {
+	*x = Coordinates{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Coordinates) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coordinates) ProtoMessage() {}
+
+func (x *Coordinates) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coordinates.ProtoReflect.Descriptor instead.
+func (*Coordinates) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Coordinates) GetLat() float64 {
+	if x != nil {
+		return x.Lat
+	}
+	return 0
+}
+
+func (x *Coordinates) GetLon() float64 {
+	if x != nil {
+		return x.Lon
+	}
+	return 0
+}
+
+// Nome da cidade qualificado pelo país, para desfazer ambiguidades (ex.: Springfield, US)
+type CityCountry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	City string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// Código ISO 3166-1 alfa-2 do país (ex.: "BR")
+	Country string `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+}
+
+func (x *CityCountry) Reset() {
+	*x = CityCountry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CityCountry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityCountry) ProtoMessage() {}
+
+func (x *CityCountry) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityCountry.ProtoReflect.Descriptor instead.
+func (*CityCountry) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CityCountry) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *CityCountry) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
 type WeatherRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	City string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// Local consultado. O nome livre (city) continua aceito, mas pode ser ambíguo.
+	//
+	// Types that are assignable to Location:
+	//	*WeatherRequest_City
+	//	*WeatherRequest_Coordinates
+	//	*WeatherRequest_CityId
+	//	*WeatherRequest_CityCountry
+	Location isWeatherRequest_Location `protobuf_oneof:"location"`
+	Units    Units                     `protobuf:"varint,2,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+	// Idioma das descrições, como tag BCP 47 (ex.: "pt-BR", "en"). Vazio usa o padrão do fornecedor.
+	Locale string `protobuf:"bytes,6,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *WeatherRequest) Reset() {
+	*x = WeatherRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WeatherRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherRequest) ProtoMessage() {}
+
+func (x *WeatherRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherRequest.ProtoReflect.Descriptor instead.
+func (*WeatherRequest) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (m *WeatherRequest) GetLocation() isWeatherRequest_Location {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+func (x *WeatherRequest) GetCity() string {
+	if x, ok := x.GetLocation().(*WeatherRequest_City); ok {
+		return x.City
+	}
+	return ""
+}
+
+func (x *WeatherRequest) GetCoordinates() *Coordinates {
+	if x, ok := x.GetLocation().(*WeatherRequest_Coordinates); ok {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *WeatherRequest) GetCityId() int64 {
+	if x, ok := x.GetLocation().(*WeatherRequest_CityId); ok {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *WeatherRequest) GetCityCountry() *CityCountry {
+	if x, ok := x.GetLocation().(*WeatherRequest_CityCountry); ok {
+		return x.CityCountry
+	}
+	return nil
+}
+
+func (x *WeatherRequest) GetUnits() Units {
+	if x != nil {
+		return x.Units
+	}
+	return Units_UNITS_UNSPECIFIED
+}
+
+func (x *WeatherRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type isWeatherRequest_Location interface {
+	isWeatherRequest_Location()
+}
+
+type WeatherRequest_City struct {
+	City string `protobuf:"bytes,1,opt,name=city,proto3,oneof"`
+}
+
+type WeatherRequest_Coordinates struct {
+	Coordinates *Coordinates `protobuf:"bytes,3,opt,name=coordinates,proto3,oneof"`
+}
+
+type WeatherRequest_CityId struct {
+	// ID da cidade no fornecedor (ex.: 3448439 = São Paulo no OpenWeather)
+	CityId int64 `protobuf:"varint,4,opt,name=city_id,json=cityId,proto3,oneof"`
+}
+
+type WeatherRequest_CityCountry struct {
+	CityCountry *CityCountry `protobuf:"bytes,5,opt,name=city_country,json=cityCountry,proto3,oneof"`
+}
+
+func (*WeatherRequest_City) isWeatherRequest_Location() {}
+
+func (*WeatherRequest_Coordinates) isWeatherRequest_Location() {}
+
+func (*WeatherRequest_CityId) isWeatherRequest_Location() {}
+
+func (*WeatherRequest_CityCountry) isWeatherRequest_Location() {}
+
+type WeatherResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Nome canônico da cidade resolvida pelo fornecedor
+	City        string  `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Temperature float32 `protobuf:"fixed32,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	Description string  `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Unidade em que os valores foram devolvidos
+	Units Units `protobuf:"varint,4,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+	// Código ISO 3166-1 alfa-2 do país da cidade resolvida
+	Country     string       `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	Coordinates *Coordinates `protobuf:"bytes,6,opt,name=coordinates,proto3" json:"coordinates,omitempty"`
+	CityId      int64        `protobuf:"varint,7,opt,name=city_id,json=cityId,proto3" json:"city_id,omitempty"`
+	// Sensação térmica, mínima e máxima observadas no momento, na mesma unidade de temperature
+	FeelsLike float32 `protobuf:"fixed32,8,opt,name=feels_like,json=feelsLike,proto3" json:"feels_like,omitempty"`
+	TempMin   float32 `protobuf:"fixed32,9,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	TempMax   float32 `protobuf:"fixed32,10,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	// Umidade relativa em %
+	Humidity int32 `protobuf:"varint,11,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	// Pressão atmosférica ao nível do mar em hPa
+	Pressure int32 `protobuf:"varint,12,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	// Velocidade do vento na unidade de units (m/s, ou mph em UNITS_IMPERIAL) e direção de origem em graus (0 = norte)
+	WindSpeed     float32 `protobuf:"fixed32,13,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	WindDirection int32   `protobuf:"varint,14,opt,name=wind_direction,json=windDirection,proto3" json:"wind_direction,omitempty"`
+	// Cobertura de nuvens em %
+	CloudCover int32 `protobuf:"varint,15,opt,name=cloud_cover,json=cloudCover,proto3" json:"cloud_cover,omitempty"`
+	// Visibilidade em metros
+	Visibility int32 `protobuf:"varint,16,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	// Nascer e pôr do sol em segundos Unix (UTC)
+	Sunrise int64 `protobuf:"varint,17,opt,name=sunrise,proto3" json:"sunrise,omitempty"`
+	Sunset  int64 `protobuf:"varint,18,opt,name=sunset,proto3" json:"sunset,omitempty"`
+	// Deslocamento do fuso horário da cidade em relação ao UTC, em segundos
+	TimezoneOffset int32 `protobuf:"varint,19,opt,name=timezone_offset,json=timezoneOffset,proto3" json:"timezone_offset,omitempty"`
+	// Código de condição do OpenWeather (ex.: 800 = céu limpo) e ícone correspondente (ex.: "01d")
+	ConditionCode int32  `protobuf:"varint,20,opt,name=condition_code,json=conditionCode,proto3" json:"condition_code,omitempty"`
+	Icon          string `protobuf:"bytes,21,opt,name=icon,proto3" json:"icon,omitempty"`
+	// Momento da observação informado pelo fornecedor, em segundos Unix (UTC)
+	ObservedAt int64 `protobuf:"varint,22,opt,name=observed_at,json=observedAt,proto3" json:"observed_at,omitempty"`
+	// Momento em que o servidor obteve os dados do fornecedor, em segundos Unix (UTC)
+	FetchedAt int64 `protobuf:"varint,23,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	// Fornecedor que produziu os dados (ex.: "openweather", "fixture")
+	Source string `protobuf:"bytes,24,opt,name=source,proto3" json:"source,omitempty"`
+	// Indica se a resposta foi servida do cache do servidor
+	Cached bool `protobuf:"varint,25,opt,name=cached,proto3" json:"cached,omitempty"`
+	// Indica que o fornecedor falhou e a resposta é a última observação conhecida da cidade
+	Stale bool `protobuf:"varint,26,opt,name=stale,proto3" json:"stale,omitempty"`
+	// Idade da observação em segundos, preenchida quando stale
+	Age int64 `protobuf:"varint,27,opt,name=age,proto3" json:"age,omitempty"`
+}
+
+func (x *WeatherResponse) Reset() {
+	*x = WeatherResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WeatherResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherResponse) ProtoMessage() {}
+
+func (x *WeatherResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherResponse.ProtoReflect.Descriptor instead.
+func (*WeatherResponse) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WeatherResponse) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *WeatherResponse) GetTemperature() float32 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *WeatherResponse) GetUnits() Units {
+	if x != nil {
+		return x.Units
+	}
+	return Units_UNITS_UNSPECIFIED
+}
+
+func (x *WeatherResponse) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *WeatherResponse) GetCoordinates() *Coordinates {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *WeatherResponse) GetCityId() int64 {
+	if x != nil {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetFeelsLike() float32 {
+	if x != nil {
+		return x.FeelsLike
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetTempMin() float32 {
+	if x != nil {
+		return x.TempMin
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetTempMax() float32 {
+	if x != nil {
+		return x.TempMax
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetPressure() int32 {
+	if x != nil {
+		return x.Pressure
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetWindSpeed() float32 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetWindDirection() int32 {
+	if x != nil {
+		return x.WindDirection
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetCloudCover() int32 {
+	if x != nil {
+		return x.CloudCover
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetVisibility() int32 {
+	if x != nil {
+		return x.Visibility
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetSunrise() int64 {
+	if x != nil {
+		return x.Sunrise
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetSunset() int64 {
+	if x != nil {
+		return x.Sunset
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetTimezoneOffset() int32 {
+	if x != nil {
+		return x.TimezoneOffset
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetConditionCode() int32 {
+	if x != nil {
+		return x.ConditionCode
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *WeatherResponse) GetObservedAt() int64 {
+	if x != nil {
+		return x.ObservedAt
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetFetchedAt() int64 {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return 0
+}
+
+func (x *WeatherResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *WeatherResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *WeatherResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *WeatherResponse) GetAge() int64 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+type WeatherBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Cities []string `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+	Units  Units    `protobuf:"varint,2,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+	Locale string   `protobuf:"bytes,3,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *WeatherBatchRequest) Reset() {
+	*x = WeatherBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WeatherBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherBatchRequest) ProtoMessage() {}
+
+func (x *WeatherBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherBatchRequest.ProtoReflect.Descriptor instead.
+func (*WeatherBatchRequest) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WeatherBatchRequest) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *WeatherBatchRequest) GetUnits() Units {
+	if x != nil {
+		return x.Units
+	}
+	return Units_UNITS_UNSPECIFIED
+}
+
+func (x *WeatherBatchRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+// Resultado de uma cidade do lote: weather preenchido em caso de sucesso, error caso contrário
+type WeatherBatchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	City    string           `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Weather *WeatherResponse `protobuf:"bytes,2,opt,name=weather,proto3" json:"weather,omitempty"`
+	Error   string           `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *WeatherBatchResult) Reset() {
+	*x = WeatherBatchResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WeatherBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherBatchResult) ProtoMessage() {}
+
+func (x *WeatherBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherBatchResult.ProtoReflect.Descriptor instead.
+func (*WeatherBatchResult) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *WeatherBatchResult) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *WeatherBatchResult) GetWeather() *WeatherResponse {
+	if x != nil {
+		return x.Weather
+	}
+	return nil
+}
+
+func (x *WeatherBatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type WeatherBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Resultados na mesma ordem das cidades pedidas
+	Results []*WeatherBatchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *WeatherBatchResponse) Reset() {
+	*x = WeatherBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WeatherBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WeatherBatchResponse) ProtoMessage() {}
+
+func (x *WeatherBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WeatherBatchResponse.ProtoReflect.Descriptor instead.
+func (*WeatherBatchResponse) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *WeatherBatchResponse) GetResults() []*WeatherBatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ForecastRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Local consultado, com as mesmas opções de WeatherRequest
+	//
+	// Types that are assignable to Location:
+	//	*ForecastRequest_City
+	//	*ForecastRequest_Coordinates
+	//	*ForecastRequest_CityId
+	//	*ForecastRequest_CityCountry
+	Location isForecastRequest_Location `protobuf_oneof:"location"`
+	// Horizonte da previsão em dias (1 a 5). Zero usa o padrão do servidor.
+	Days   int32  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	Units  Units  `protobuf:"varint,3,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+	Locale string `protobuf:"bytes,7,opt,name=locale,proto3" json:"locale,omitempty"`
+}
+
+func (x *ForecastRequest) Reset() {
+	*x = ForecastRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForecastRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastRequest) ProtoMessage() {}
+
+func (x *ForecastRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastRequest.ProtoReflect.Descriptor instead.
+func (*ForecastRequest) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (m *ForecastRequest) GetLocation() isForecastRequest_Location {
+	if m != nil {
+		return m.Location
+	}
+	return nil
+}
+
+func (x *ForecastRequest) GetCity() string {
+	if x, ok := x.GetLocation().(*ForecastRequest_City); ok {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ForecastRequest) GetCoordinates() *Coordinates {
+	if x, ok := x.GetLocation().(*ForecastRequest_Coordinates); ok {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *ForecastRequest) GetCityId() int64 {
+	if x, ok := x.GetLocation().(*ForecastRequest_CityId); ok {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *ForecastRequest) GetCityCountry() *CityCountry {
+	if x, ok := x.GetLocation().(*ForecastRequest_CityCountry); ok {
+		return x.CityCountry
+	}
+	return nil
+}
+
+func (x *ForecastRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *ForecastRequest) GetUnits() Units {
+	if x != nil {
+		return x.Units
+	}
+	return Units_UNITS_UNSPECIFIED
+}
+
+func (x *ForecastRequest) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+type isForecastRequest_Location interface {
+	isForecastRequest_Location()
+}
+
+type ForecastRequest_City struct {
+	City string `protobuf:"bytes,1,opt,name=city,proto3,oneof"`
+}
+
+type ForecastRequest_Coordinates struct {
+	Coordinates *Coordinates `protobuf:"bytes,4,opt,name=coordinates,proto3,oneof"`
+}
+
+type ForecastRequest_CityId struct {
+	CityId int64 `protobuf:"varint,5,opt,name=city_id,json=cityId,proto3,oneof"`
+}
+
+type ForecastRequest_CityCountry struct {
+	CityCountry *CityCountry `protobuf:"bytes,6,opt,name=city_country,json=cityCountry,proto3,oneof"`
+}
+
+func (*ForecastRequest_City) isForecastRequest_Location() {}
+
+func (*ForecastRequest_Coordinates) isForecastRequest_Location() {}
+
+func (*ForecastRequest_CityId) isForecastRequest_Location() {}
+
+func (*ForecastRequest_CityCountry) isForecastRequest_Location() {}
+
+// Entrada de previsão, usada tanto para os intervalos horários quanto para os dias
+type ForecastEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Início do intervalo em segundos Unix (UTC)
+	Time        int64   `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	Temperature float32 `protobuf:"fixed32,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	TempMin     float32 `protobuf:"fixed32,3,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	TempMax     float32 `protobuf:"fixed32,4,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	// Probabilidade de precipitação entre 0 e 1
+	PrecipitationProbability float32 `protobuf:"fixed32,5,opt,name=precipitation_probability,json=precipitationProbability,proto3" json:"precipitation_probability,omitempty"`
+	// Velocidade do vento na unidade de units da resposta (m/s, ou mph em UNITS_IMPERIAL)
+	WindSpeed float32 `protobuf:"fixed32,6,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	// Umidade relativa em %
+	Humidity int32 `protobuf:"varint,7,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	// Código de condição do OpenWeather (ex.: 800 = céu limpo)
+	ConditionCode int32  `protobuf:"varint,8,opt,name=condition_code,json=conditionCode,proto3" json:"condition_code,omitempty"`
+	Description   string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+}
+
+func (x *ForecastEntry) Reset() {
+	*x = ForecastEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForecastEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastEntry) ProtoMessage() {}
+
+func (x *ForecastEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastEntry.ProtoReflect.Descriptor instead.
+func (*ForecastEntry) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ForecastEntry) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetTemperature() float32 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetTempMin() float32 {
+	if x != nil {
+		return x.TempMin
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetTempMax() float32 {
+	if x != nil {
+		return x.TempMax
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetPrecipitationProbability() float32 {
+	if x != nil {
+		return x.PrecipitationProbability
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetWindSpeed() float32 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetConditionCode() int32 {
+	if x != nil {
+		return x.ConditionCode
+	}
+	return 0
+}
+
+func (x *ForecastEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type ForecastResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Nome canônico da cidade resolvida pelo fornecedor
+	City        string           `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Hourly      []*ForecastEntry `protobuf:"bytes,2,rep,name=hourly,proto3" json:"hourly,omitempty"`
+	Daily       []*ForecastEntry `protobuf:"bytes,3,rep,name=daily,proto3" json:"daily,omitempty"`
+	Units       Units            `protobuf:"varint,4,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+	Country     string           `protobuf:"bytes,5,opt,name=country,proto3" json:"country,omitempty"`
+	Coordinates *Coordinates     `protobuf:"bytes,6,opt,name=coordinates,proto3" json:"coordinates,omitempty"`
+	CityId      int64            `protobuf:"varint,7,opt,name=city_id,json=cityId,proto3" json:"city_id,omitempty"`
+	// Fornecedor que produziu a previsão (ex.: "openweather", "fixture")
+	Source string `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`
+}
+
+func (x *ForecastResponse) Reset() {
+	*x = ForecastResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForecastResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastResponse) ProtoMessage() {}
+
+func (x *ForecastResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastResponse.ProtoReflect.Descriptor instead.
+func (*ForecastResponse) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ForecastResponse) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *ForecastResponse) GetHourly() []*ForecastEntry {
+	if x != nil {
+		return x.Hourly
+	}
+	return nil
+}
+
+func (x *ForecastResponse) GetDaily() []*ForecastEntry {
+	if x != nil {
+		return x.Daily
+	}
+	return nil
+}
+
+func (x *ForecastResponse) GetUnits() Units {
+	if x != nil {
+		return x.Units
+	}
+	return Units_UNITS_UNSPECIFIED
+}
+
+func (x *ForecastResponse) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *ForecastResponse) GetCoordinates() *Coordinates {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+func (x *ForecastResponse) GetCityId() int64 {
+	if x != nil {
+		return x.CityId
+	}
+	return 0
+}
+
+func (x *ForecastResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type SearchCitiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Texto digitado pelo usuário, opcionalmente qualificado (ex.: "sao pa" ou "springfield, us")
+	Query string `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Quantidade máxima de resultados (1 a 20). Zero usa o padrão do servidor.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SearchCitiesRequest) Reset() {
+	*x = SearchCitiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchCitiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCitiesRequest) ProtoMessage() {}
+
+func (x *SearchCitiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCitiesRequest.ProtoReflect.Descriptor instead.
+func (*SearchCitiesRequest) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchCitiesRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchCitiesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Cidade encontrada pela busca, com os dados para consultá-la sem ambiguidade
+type CityMatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Código ISO 3166-1 alfa-2 do país
+	Country string `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	// Estado ou região (ex.: "Santa Catarina", "Illinois")
+	Region      string       `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	Coordinates *Coordinates `protobuf:"bytes,4,opt,name=coordinates,proto3" json:"coordinates,omitempty"`
+}
+
+func (x *CityMatch) Reset() {
+	*x = CityMatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CityMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CityMatch) ProtoMessage() {}
+
+func (x *CityMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CityMatch.ProtoReflect.Descriptor instead.
+func (*CityMatch) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CityMatch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CityMatch) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CityMatch) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *CityMatch) GetCoordinates() *Coordinates {
+	if x != nil {
+		return x.Coordinates
+	}
+	return nil
+}
+
+type SearchCitiesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Cidades ordenadas da mais para a menos relevante
+	Cities []*CityMatch `protobuf:"bytes,1,rep,name=cities,proto3" json:"cities,omitempty"`
+}
+
+func (x *SearchCitiesResponse) Reset() {
+	*x = SearchCitiesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchCitiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCitiesResponse) ProtoMessage() {}
+
+func (x *SearchCitiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCitiesResponse.ProtoReflect.Descriptor instead.
+func (*SearchCitiesResponse) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchCitiesResponse) GetCities() []*CityMatch {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+type HistoryRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Nome da cidade, como devolvido em WeatherResponse (acentos e maiúsculas são ignorados)
+	City string `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	// Código ISO 3166-1 alfa-2 do país, para desfazer ambiguidades (opcional)
+	Country string `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	// Intervalo [from, to) em segundos Unix (UTC), com o fim exclusivo.
+	// Zero em "to" usa o momento atual; zero em "from", 24 horas antes de "to".
+	From        int64       `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`
+	To          int64       `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`
+	Granularity Granularity `protobuf:"varint,5,opt,name=granularity,proto3,enum=web.Granularity" json:"granularity,omitempty"`
+	Units       Units       `protobuf:"varint,6,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_weather_service_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *HistoryRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_UNSPECIFIED
+}
+
+func (x *HistoryRequest) GetUnits() Units {
+	if x != nil {
+		return x.Units
+	}
+	return Units_UNITS_UNSPECIFIED
+}
+
+// Ponto da série histórica: uma observação ou o resumo das observações de um intervalo
+type HistoryPoint struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Momento da observação ou início do intervalo, em segundos Unix (UTC)
+	Time int64 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Temperatura (média do intervalo), mínima e máxima: as informadas pelo fornecedor
+	// em cada observação (raw) ou as observadas no intervalo (hourly e daily)
+	Temperature float32 `protobuf:"fixed32,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	TempMin     float32 `protobuf:"fixed32,3,opt,name=temp_min,json=tempMin,proto3" json:"temp_min,omitempty"`
+	TempMax     float32 `protobuf:"fixed32,4,opt,name=temp_max,json=tempMax,proto3" json:"temp_max,omitempty"`
+	// Médias do intervalo: umidade em %, pressão em hPa e vento na unidade de units (m/s, ou mph em UNITS_IMPERIAL)
+	Humidity  int32   `protobuf:"varint,5,opt,name=humidity,proto3" json:"humidity,omitempty"`
+	Pressure  int32   `protobuf:"varint,6,opt,name=pressure,proto3" json:"pressure,omitempty"`
+	WindSpeed float32 `protobuf:"fixed32,7,opt,name=wind_speed,json=windSpeed,proto3" json:"wind_speed,omitempty"`
+	// Condição da última observação do intervalo
+	ConditionCode int32  `protobuf:"varint,8,opt,name=condition_code,json=conditionCode,proto3" json:"condition_code,omitempty"`
+	Description   string `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	// Quantidade de observações resumidas no ponto
+	Samples int32 `protobuf:"varint,10,opt,name=samples,proto3" json:"samples,omitempty"`
 }
 
-func (x *WeatherRequest) Reset() {
-	*x = WeatherRequest{}
+func (x *HistoryPoint) Reset() {
+	*x = HistoryPoint{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_weather_service_proto_msgTypes[0]
+		mi := &file_weather_service_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *WeatherRequest) String() string {
+func (x *HistoryPoint) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WeatherRequest) ProtoMessage() {}
+func (*HistoryPoint) ProtoMessage() {}
 
-func (x *WeatherRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_weather_service_proto_msgTypes[0]
+func (x *HistoryPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,45 +1493,112 @@ func (x *WeatherRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WeatherRequest.ProtoReflect.Descriptor instead.
-func (*WeatherRequest) Descriptor() ([]byte, []int) {
-	return file_weather_service_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use HistoryPoint.ProtoReflect.Descriptor instead.
+func (*HistoryPoint) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *WeatherRequest) GetCity() string {
+func (x *HistoryPoint) GetTime() int64 {
 	if x != nil {
-		return x.City
+		return x.Time
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetTemperature() float32 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetTempMin() float32 {
+	if x != nil {
+		return x.TempMin
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetTempMax() float32 {
+	if x != nil {
+		return x.TempMax
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetHumidity() int32 {
+	if x != nil {
+		return x.Humidity
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetPressure() int32 {
+	if x != nil {
+		return x.Pressure
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetWindSpeed() float32 {
+	if x != nil {
+		return x.WindSpeed
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetConditionCode() int32 {
+	if x != nil {
+		return x.ConditionCode
+	}
+	return 0
+}
+
+func (x *HistoryPoint) GetDescription() string {
+	if x != nil {
+		return x.Description
 	}
 	return ""
 }
 
-type WeatherResponse struct {
+func (x *HistoryPoint) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+type HistoryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	City        string  `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
-	Temperature float32 `protobuf:"fixed32,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
-	Description string  `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Nome canônico da cidade e país das observações
+	City        string      `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Country     string      `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	Units       Units       `protobuf:"varint,3,opt,name=units,proto3,enum=web.Units" json:"units,omitempty"`
+	Granularity Granularity `protobuf:"varint,4,opt,name=granularity,proto3,enum=web.Granularity" json:"granularity,omitempty"`
+	// Pontos em ordem cronológica
+	Points []*HistoryPoint `protobuf:"bytes,5,rep,name=points,proto3" json:"points,omitempty"`
 }
 
-func (x *WeatherResponse) Reset() {
-	*x = WeatherResponse{}
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_weather_service_proto_msgTypes[1]
+		mi := &file_weather_service_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *WeatherResponse) String() string {
+func (x *HistoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WeatherResponse) ProtoMessage() {}
+func (*HistoryResponse) ProtoMessage() {}
 
-func (x *WeatherResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_weather_service_proto_msgTypes[1]
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_weather_service_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -104,53 +1609,297 @@ func (x *WeatherResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WeatherResponse.ProtoReflect.Descriptor instead.
-func (*WeatherResponse) Descriptor() ([]byte, []int) {
-	return file_weather_service_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_weather_service_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *WeatherResponse) GetCity() string {
+func (x *HistoryResponse) GetCity() string {
 	if x != nil {
 		return x.City
 	}
 	return ""
 }
 
-func (x *WeatherResponse) GetTemperature() float32 {
+func (x *HistoryResponse) GetCountry() string {
 	if x != nil {
-		return x.Temperature
+		return x.Country
 	}
-	return 0
+	return ""
 }
 
-func (x *WeatherResponse) GetDescription() string {
+func (x *HistoryResponse) GetUnits() Units {
 	if x != nil {
-		return x.Description
+		return x.Units
 	}
-	return ""
+	return Units_UNITS_UNSPECIFIED
+}
+
+func (x *HistoryResponse) GetGranularity() Granularity {
+	if x != nil {
+		return x.Granularity
+	}
+	return Granularity_GRANULARITY_UNSPECIFIED
+}
+
+func (x *HistoryResponse) GetPoints() []*HistoryPoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
 }
 
 var File_weather_service_proto protoreflect.FileDescriptor
 
 var file_weather_service_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x77, 0x65, 0x62, 0x22, 0x24, 0x0a, 0x0e,
-	0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x77, 0x65, 0x62, 0x22, 0x31, 0x0a, 0x0b,
+	0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x6c,
+	0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x61, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x6c, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6c, 0x6f, 0x6e, 0x22,
+	0x3b, 0x0a, 0x0b, 0x43, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69,
+	0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x22, 0xf4, 0x01, 0x0a,
+	0x0e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x14, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x34, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x65, 0x62,
+	0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x48, 0x00, 0x52, 0x0b,
+	0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x07, 0x63,
+	0x69, 0x74, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x06,
+	0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x0c, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77,
+	0x65, 0x62, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x48, 0x00,
+	0x52, 0x0b, 0x63, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x20, 0x0a,
+	0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x77,
+	0x65, 0x62, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x42, 0x0a, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0xb4, 0x06, 0x0a, 0x0f, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02,
+	0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x20, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a,
+	0x2e, 0x77, 0x65, 0x62, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x0b, 0x63,
+	0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74,
+	0x65, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12,
+	0x17, 0x0a, 0x07, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x65, 0x65, 0x6c,
+	0x73, 0x5f, 0x6c, 0x69, 0x6b, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x66, 0x65,
+	0x65, 0x6c, 0x73, 0x4c, 0x69, 0x6b, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f,
+	0x6d, 0x69, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x4d,
+	0x69, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x4d, 0x61, 0x78, 0x12, 0x1a, 0x0a,
+	0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x75, 0x72, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x75, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x73, 0x70,
+	0x65, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x53,
+	0x70, 0x65, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x77, 0x69,
+	0x6e, 0x64, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x63,
+	0x6c, 0x6f, 0x75, 0x64, 0x5f, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x63, 0x6c, 0x6f, 0x75, 0x64, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a,
+	0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x75, 0x6e, 0x72, 0x69, 0x73, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x73,
+	0x75, 0x6e, 0x72, 0x69, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x6e, 0x73, 0x65, 0x74,
+	0x18, 0x12, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x73, 0x75, 0x6e, 0x73, 0x65, 0x74, 0x12, 0x27,
+	0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e,
+	0x65, 0x4f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x64, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x14, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0d, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x15, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x69, 0x63,
+	0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x16, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x6f, 0x62, 0x73, 0x65, 0x72, 0x76, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x17, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x64,
+	0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x18, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x64, 0x18, 0x19, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x61, 0x63, 0x68,
+	0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x05, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x67, 0x65, 0x18,
+	0x1b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x61, 0x67, 0x65, 0x22, 0x67, 0x0a, 0x13, 0x57, 0x65,
+	0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x06, 0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x6e, 0x69,
+	0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x55,
+	0x6e, 0x69, 0x74, 0x73, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x65, 0x22, 0x6e, 0x0a, 0x12, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x2e, 0x0a,
+	0x07, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x07, 0x77, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x22, 0x49, 0x0a, 0x14, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x72,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x77,
+	0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x89,
+	0x02, 0x0a, 0x0f, 0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x14, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x34, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x77, 0x65, 0x62, 0x2e, 0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x48,
+	0x00, 0x52, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x19,
+	0x0a, 0x07, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x00, 0x52, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x0c, 0x63, 0x69, 0x74,
+	0x79, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72,
+	0x79, 0x48, 0x00, 0x52, 0x0b, 0x63, 0x69, 0x74, 0x79, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x64, 0x61, 0x79, 0x73, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52,
+	0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x65, 0x42, 0x0a,
+	0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xbc, 0x02, 0x0a, 0x0d, 0x46,
+	0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65,
+	0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6d, 0x69, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x4d, 0x69, 0x6e, 0x12, 0x19, 0x0a,
+	0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x02, 0x52,
+	0x07, 0x74, 0x65, 0x6d, 0x70, 0x4d, 0x61, 0x78, 0x12, 0x3b, 0x0a, 0x19, 0x70, 0x72, 0x65, 0x63,
+	0x69, 0x70, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x02, 0x52, 0x18, 0x70, 0x72, 0x65,
+	0x63, 0x69, 0x70, 0x69, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62,
+	0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x73, 0x70,
+	0x65, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x53,
+	0x70, 0x65, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79,
+	0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9d, 0x02, 0x0a, 0x10, 0x46, 0x6f,
+	0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12,
 	0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69,
-	0x74, 0x79, 0x22, 0x69, 0x0a, 0x0f, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d,
-	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b,
-	0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64,
-	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x32, 0x49, 0x0a,
-	0x0e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
-	0x37, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12, 0x13, 0x2e,
-	0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x14, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x11, 0x5a, 0x0f, 0x67, 0x72, 0x70, 0x63,
-	0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2f, 0x77, 0x65, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x74, 0x79, 0x12, 0x2a, 0x0a, 0x06, 0x68, 0x6f, 0x75, 0x72, 0x6c, 0x79, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73,
+	0x74, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x68, 0x6f, 0x75, 0x72, 0x6c, 0x79, 0x12, 0x28,
+	0x0a, 0x05, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x77, 0x65, 0x62, 0x2e, 0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x05, 0x64, 0x61, 0x69, 0x6c, 0x79, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x55, 0x6e,
+	0x69, 0x74, 0x73, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61,
+	0x74, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x65, 0x62, 0x2e,
+	0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6f,
+	0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x69, 0x74, 0x79,
+	0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x63, 0x69, 0x74, 0x79, 0x49,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x41, 0x0a, 0x13, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x85, 0x01, 0x0a,
+	0x09, 0x43, 0x69, 0x74, 0x79, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x67, 0x69,
+	0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x67, 0x69, 0x6f, 0x6e,
+	0x12, 0x32, 0x0a, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x43, 0x6f, 0x6f, 0x72,
+	0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x73, 0x52, 0x0b, 0x63, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e,
+	0x61, 0x74, 0x65, 0x73, 0x22, 0x3e, 0x0a, 0x14, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x06,
+	0x63, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x77,
+	0x65, 0x62, 0x2e, 0x43, 0x69, 0x74, 0x79, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x06, 0x63, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x22, 0xb8, 0x01, 0x0a, 0x0e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69, 0x74, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x12, 0x0e, 0x0a, 0x02, 0x74, 0x6f, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x74, 0x6f, 0x12, 0x32, 0x0a, 0x0b, 0x67, 0x72, 0x61,
+	0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10,
+	0x2e, 0x77, 0x65, 0x62, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79,
+	0x52, 0x0b, 0x67, 0x72, 0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a,
+	0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x77,
+	0x65, 0x62, 0x2e, 0x55, 0x6e, 0x69, 0x74, 0x73, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x22,
+	0xb4, 0x02, 0x0a, 0x0c, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x6f, 0x69, 0x6e, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6d,
+	0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x4d, 0x69,
+	0x6e, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x02, 0x52, 0x07, 0x74, 0x65, 0x6d, 0x70, 0x4d, 0x61, 0x78, 0x12, 0x1a, 0x0a, 0x08,
+	0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x68, 0x75, 0x6d, 0x69, 0x64, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x75, 0x72, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x5f, 0x73, 0x70, 0x65,
+	0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x09, 0x77, 0x69, 0x6e, 0x64, 0x53, 0x70,
+	0x65, 0x65, 0x64, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x63, 0x6f, 0x6e,
+	0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x73,
+	0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x0f, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x69,
+	0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x69, 0x74, 0x79, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x20, 0x0a, 0x05, 0x75, 0x6e, 0x69, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0a, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x55, 0x6e,
+	0x69, 0x74, 0x73, 0x52, 0x05, 0x75, 0x6e, 0x69, 0x74, 0x73, 0x12, 0x32, 0x0a, 0x0b, 0x67, 0x72,
+	0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x10, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x47, 0x72, 0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74,
+	0x79, 0x52, 0x0b, 0x67, 0x72, 0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69, 0x74, 0x79, 0x12, 0x29,
+	0x0a, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x77, 0x65, 0x62, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x6f, 0x69, 0x6e,
+	0x74, 0x52, 0x06, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x2a, 0x58, 0x0a, 0x05, 0x55, 0x6e, 0x69,
+	0x74, 0x73, 0x12, 0x15, 0x0a, 0x11, 0x55, 0x4e, 0x49, 0x54, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x4e, 0x49,
+	0x54, 0x53, 0x5f, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x55,
+	0x4e, 0x49, 0x54, 0x53, 0x5f, 0x49, 0x4d, 0x50, 0x45, 0x52, 0x49, 0x41, 0x4c, 0x10, 0x02, 0x12,
+	0x12, 0x0a, 0x0e, 0x55, 0x4e, 0x49, 0x54, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x4e, 0x44, 0x41, 0x52,
+	0x44, 0x10, 0x03, 0x2a, 0x6e, 0x0a, 0x0b, 0x47, 0x72, 0x61, 0x6e, 0x75, 0x6c, 0x61, 0x72, 0x69,
+	0x74, 0x79, 0x12, 0x1b, 0x0a, 0x17, 0x47, 0x52, 0x41, 0x4e, 0x55, 0x4c, 0x41, 0x52, 0x49, 0x54,
+	0x59, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x13, 0x0a, 0x0f, 0x47, 0x52, 0x41, 0x4e, 0x55, 0x4c, 0x41, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x52,
+	0x41, 0x57, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x47, 0x52, 0x41, 0x4e, 0x55, 0x4c, 0x41, 0x52,
+	0x49, 0x54, 0x59, 0x5f, 0x48, 0x4f, 0x55, 0x52, 0x4c, 0x59, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11,
+	0x47, 0x52, 0x41, 0x4e, 0x55, 0x4c, 0x41, 0x52, 0x49, 0x54, 0x59, 0x5f, 0x44, 0x41, 0x49, 0x4c,
+	0x59, 0x10, 0x03, 0x32, 0x8c, 0x03, 0x0a, 0x0e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x53,
+	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x57, 0x65, 0x61,
+	0x74, 0x68, 0x65, 0x72, 0x12, 0x13, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68,
+	0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x77, 0x65, 0x62, 0x2e,
+	0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x3a, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x12, 0x14,
+	0x2e, 0x77, 0x65, 0x62, 0x2e, 0x46, 0x6f, 0x72, 0x65, 0x63, 0x61, 0x73, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x46, 0x6f, 0x72, 0x65, 0x63,
+	0x61, 0x73, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x10, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x12,
+	0x13, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x46, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x18, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x77, 0x65, 0x62, 0x2e,
+	0x57, 0x65, 0x61, 0x74, 0x68, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x12, 0x18, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63,
+	0x68, 0x43, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19,
+	0x2e, 0x77, 0x65, 0x62, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x43, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x0a, 0x47, 0x65, 0x74,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x13, 0x2e, 0x77, 0x65, 0x62, 0x2e, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x77,
+	0x65, 0x62, 0x2e, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x11, 0x5a, 0x0f, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x2f, 0x77, 0x65, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -165,19 +1914,68 @@ func file_weather_service_proto_rawDescGZIP() []byte {
 	return file_weather_service_proto_rawDescData
 }
 
-var file_weather_service_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_weather_service_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_weather_service_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_weather_service_proto_goTypes = []any{
-	(*WeatherRequest)(nil),  // 0: web.WeatherRequest
-	(*WeatherResponse)(nil), // 1: web.WeatherResponse
+	(Units)(0),                   // 0: web.Units
+	(Granularity)(0),             // 1: web.Granularity
+	(*Coordinates)(nil),          // 2: web.Coordinates
+	(*CityCountry)(nil),          // 3: web.CityCountry
+	(*WeatherRequest)(nil),       // 4: web.WeatherRequest
+	(*WeatherResponse)(nil),      // 5: web.WeatherResponse
+	(*WeatherBatchRequest)(nil),  // 6: web.WeatherBatchRequest
+	(*WeatherBatchResult)(nil),   // 7: web.WeatherBatchResult
+	(*WeatherBatchResponse)(nil), // 8: web.WeatherBatchResponse
+	(*ForecastRequest)(nil),      // 9: web.ForecastRequest
+	(*ForecastEntry)(nil),        // 10: web.ForecastEntry
+	(*ForecastResponse)(nil),     // 11: web.ForecastResponse
+	(*SearchCitiesRequest)(nil),  // 12: web.SearchCitiesRequest
+	(*CityMatch)(nil),            // 13: web.CityMatch
+	(*SearchCitiesResponse)(nil), // 14: web.SearchCitiesResponse
+	(*HistoryRequest)(nil),       // 15: web.HistoryRequest
+	(*HistoryPoint)(nil),         // 16: web.HistoryPoint
+	(*HistoryResponse)(nil),      // 17: web.HistoryResponse
 }
 var file_weather_service_proto_depIdxs = []int32{
-	0, // 0: web.WeatherService.GetWeather:input_type -> web.WeatherRequest
-	1, // 1: web.WeatherService.GetWeather:output_type -> web.WeatherResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2,  // 0: web.WeatherRequest.coordinates:type_name -> web.Coordinates
+	3,  // 1: web.WeatherRequest.city_country:type_name -> web.CityCountry
+	0,  // 2: web.WeatherRequest.units:type_name -> web.Units
+	0,  // 3: web.WeatherResponse.units:type_name -> web.Units
+	2,  // 4: web.WeatherResponse.coordinates:type_name -> web.Coordinates
+	0,  // 5: web.WeatherBatchRequest.units:type_name -> web.Units
+	5,  // 6: web.WeatherBatchResult.weather:type_name -> web.WeatherResponse
+	7,  // 7: web.WeatherBatchResponse.results:type_name -> web.WeatherBatchResult
+	2,  // 8: web.ForecastRequest.coordinates:type_name -> web.Coordinates
+	3,  // 9: web.ForecastRequest.city_country:type_name -> web.CityCountry
+	0,  // 10: web.ForecastRequest.units:type_name -> web.Units
+	10, // 11: web.ForecastResponse.hourly:type_name -> web.ForecastEntry
+	10, // 12: web.ForecastResponse.daily:type_name -> web.ForecastEntry
+	0,  // 13: web.ForecastResponse.units:type_name -> web.Units
+	2,  // 14: web.ForecastResponse.coordinates:type_name -> web.Coordinates
+	2,  // 15: web.CityMatch.coordinates:type_name -> web.Coordinates
+	13, // 16: web.SearchCitiesResponse.cities:type_name -> web.CityMatch
+	1,  // 17: web.HistoryRequest.granularity:type_name -> web.Granularity
+	0,  // 18: web.HistoryRequest.units:type_name -> web.Units
+	0,  // 19: web.HistoryResponse.units:type_name -> web.Units
+	1,  // 20: web.HistoryResponse.granularity:type_name -> web.Granularity
+	16, // 21: web.HistoryResponse.points:type_name -> web.HistoryPoint
+	4,  // 22: web.WeatherService.GetWeather:input_type -> web.WeatherRequest
+	9,  // 23: web.WeatherService.GetForecast:input_type -> web.ForecastRequest
+	4,  // 24: web.WeatherService.SubscribeWeather:input_type -> web.WeatherRequest
+	6,  // 25: web.WeatherService.GetWeatherBatch:input_type -> web.WeatherBatchRequest
+	12, // 26: web.WeatherService.SearchCities:input_type -> web.SearchCitiesRequest
+	15, // 27: web.WeatherService.GetHistory:input_type -> web.HistoryRequest
+	5,  // 28: web.WeatherService.GetWeather:output_type -> web.WeatherResponse
+	11, // 29: web.WeatherService.GetForecast:output_type -> web.ForecastResponse
+	5,  // 30: web.WeatherService.SubscribeWeather:output_type -> web.WeatherResponse
+	8,  // 31: web.WeatherService.GetWeatherBatch:output_type -> web.WeatherBatchResponse
+	14, // 32: web.WeatherService.SearchCities:output_type -> web.SearchCitiesResponse
+	17, // 33: web.WeatherService.GetHistory:output_type -> web.HistoryResponse
+	28, // [28:34] is the sub-list for method output_type
+	22, // [22:28] is the sub-list for method input_type
+	22, // [22:22] is the sub-list for extension type_name
+	22, // [22:22] is the sub-list for extension extendee
+	0,  // [0:22] is the sub-list for field type_name
 }
 
 func init() { file_weather_service_proto_init() }
@@ -187,7 +1985,7 @@ func file_weather_service_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_weather_service_proto_msgTypes[0].Exporter = func(v any, i int) any {
-			switch v := v.(*WeatherRequest); i {
+			switch v := v.(*Coordinates); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -199,6 +1997,30 @@ func file_weather_service_proto_init() {
 			}
 		}
 		file_weather_service_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*CityCountry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*WeatherRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[3].Exporter = func(v any, i int) any {
 			switch v := v.(*WeatherResponse); i {
 			case 0:
 				return &v.state
@@ -210,19 +2032,176 @@ func file_weather_service_proto_init() {
 				return nil
 			}
 		}
+		file_weather_service_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*WeatherBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*WeatherBatchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*WeatherBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ForecastRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ForecastEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*ForecastResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchCitiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CityMatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchCitiesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*HistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*HistoryPoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_weather_service_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*HistoryResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_weather_service_proto_msgTypes[2].OneofWrappers = []any{
+		(*WeatherRequest_City)(nil),
+		(*WeatherRequest_Coordinates)(nil),
+		(*WeatherRequest_CityId)(nil),
+		(*WeatherRequest_CityCountry)(nil),
+	}
+	file_weather_service_proto_msgTypes[7].OneofWrappers = []any{
+		(*ForecastRequest_City)(nil),
+		(*ForecastRequest_Coordinates)(nil),
+		(*ForecastRequest_CityId)(nil),
+		(*ForecastRequest_CityCountry)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_weather_service_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_weather_service_proto_goTypes,
 		DependencyIndexes: file_weather_service_proto_depIdxs,
+		EnumInfos:         file_weather_service_proto_enumTypes,
 		MessageInfos:      file_weather_service_proto_msgTypes,
 	}.Build()
 	File_weather_service_proto = out.File
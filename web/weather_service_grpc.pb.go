@@ -19,7 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	WeatherService_GetWeather_FullMethodName = "/web.WeatherService/GetWeather"
+	WeatherService_GetWeather_FullMethodName       = "/web.WeatherService/GetWeather"
+	WeatherService_GetForecast_FullMethodName      = "/web.WeatherService/GetForecast"
+	WeatherService_SubscribeWeather_FullMethodName = "/web.WeatherService/SubscribeWeather"
+	WeatherService_GetWeatherBatch_FullMethodName  = "/web.WeatherService/GetWeatherBatch"
+	WeatherService_SearchCities_FullMethodName     = "/web.WeatherService/SearchCities"
+	WeatherService_GetHistory_FullMethodName       = "/web.WeatherService/GetHistory"
 )
 
 // WeatherServiceClient is the client API for WeatherService service.
@@ -29,6 +34,15 @@ const (
 // Definição do serviço gRPC
 type WeatherServiceClient interface {
 	GetWeather(ctx context.Context, in *WeatherRequest, opts ...grpc.CallOption) (*WeatherResponse, error)
+	GetForecast(ctx context.Context, in *ForecastRequest, opts ...grpc.CallOption) (*ForecastResponse, error)
+	// Envia o clima atual da cidade e uma nova mensagem sempre que ele mudar
+	SubscribeWeather(ctx context.Context, in *WeatherRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WeatherResponse], error)
+	// Busca o clima de várias cidades em uma única chamada
+	GetWeatherBatch(ctx context.Context, in *WeatherBatchRequest, opts ...grpc.CallOption) (*WeatherBatchResponse, error)
+	// Busca cidades pelo nome, para sugestões de autocompletar
+	SearchCities(ctx context.Context, in *SearchCitiesRequest, opts ...grpc.CallOption) (*SearchCitiesResponse, error)
+	// Retorna as observações registradas pelo servidor para a cidade em um intervalo de datas
+	GetHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
 }
 
 type weatherServiceClient struct {
@@ -49,6 +63,65 @@ func (c *weatherServiceClient) GetWeather(ctx context.Context, in *WeatherReques
 	return out, nil
 }
 
+func (c *weatherServiceClient) GetForecast(ctx context.Context, in *ForecastRequest, opts ...grpc.CallOption) (*ForecastResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForecastResponse)
+	err := c.cc.Invoke(ctx, WeatherService_GetForecast_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) SubscribeWeather(ctx context.Context, in *WeatherRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WeatherResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WeatherService_ServiceDesc.Streams[0], WeatherService_SubscribeWeather_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WeatherRequest, WeatherResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_SubscribeWeatherClient = grpc.ServerStreamingClient[WeatherResponse]
+
+func (c *weatherServiceClient) GetWeatherBatch(ctx context.Context, in *WeatherBatchRequest, opts ...grpc.CallOption) (*WeatherBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WeatherBatchResponse)
+	err := c.cc.Invoke(ctx, WeatherService_GetWeatherBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) SearchCities(ctx context.Context, in *SearchCitiesRequest, opts ...grpc.CallOption) (*SearchCitiesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchCitiesResponse)
+	err := c.cc.Invoke(ctx, WeatherService_SearchCities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *weatherServiceClient) GetHistory(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, WeatherService_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WeatherServiceServer is the server API for WeatherService service.
 // All implementations must embed UnimplementedWeatherServiceServer
 // for forward compatibility.
@@ -56,6 +129,15 @@ func (c *weatherServiceClient) GetWeather(ctx context.Context, in *WeatherReques
 // Definição do serviço gRPC
 type WeatherServiceServer interface {
 	GetWeather(context.Context, *WeatherRequest) (*WeatherResponse, error)
+	GetForecast(context.Context, *ForecastRequest) (*ForecastResponse, error)
+	// Envia o clima atual da cidade e uma nova mensagem sempre que ele mudar
+	SubscribeWeather(*WeatherRequest, grpc.ServerStreamingServer[WeatherResponse]) error
+	// Busca o clima de várias cidades em uma única chamada
+	GetWeatherBatch(context.Context, *WeatherBatchRequest) (*WeatherBatchResponse, error)
+	// Busca cidades pelo nome, para sugestões de autocompletar
+	SearchCities(context.Context, *SearchCitiesRequest) (*SearchCitiesResponse, error)
+	// Retorna as observações registradas pelo servidor para a cidade em um intervalo de datas
+	GetHistory(context.Context, *HistoryRequest) (*HistoryResponse, error)
 	mustEmbedUnimplementedWeatherServiceServer()
 }
 
@@ -69,6 +151,21 @@ type UnimplementedWeatherServiceServer struct{}
 func (UnimplementedWeatherServiceServer) GetWeather(context.Context, *WeatherRequest) (*WeatherResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetWeather not implemented")
 }
+func (UnimplementedWeatherServiceServer) GetForecast(context.Context, *ForecastRequest) (*ForecastResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetForecast not implemented")
+}
+func (UnimplementedWeatherServiceServer) SubscribeWeather(*WeatherRequest, grpc.ServerStreamingServer[WeatherResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeWeather not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetWeatherBatch(context.Context, *WeatherBatchRequest) (*WeatherBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWeatherBatch not implemented")
+}
+func (UnimplementedWeatherServiceServer) SearchCities(context.Context, *SearchCitiesRequest) (*SearchCitiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchCities not implemented")
+}
+func (UnimplementedWeatherServiceServer) GetHistory(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
 func (UnimplementedWeatherServiceServer) mustEmbedUnimplementedWeatherServiceServer() {}
 func (UnimplementedWeatherServiceServer) testEmbeddedByValue()                        {}
 
@@ -108,6 +205,89 @@ func _WeatherService_GetWeather_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WeatherService_GetForecast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForecastRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetForecast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetForecast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetForecast(ctx, req.(*ForecastRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_SubscribeWeather_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WeatherRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WeatherServiceServer).SubscribeWeather(m, &grpc.GenericServerStream[WeatherRequest, WeatherResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WeatherService_SubscribeWeatherServer = grpc.ServerStreamingServer[WeatherResponse]
+
+func _WeatherService_GetWeatherBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WeatherBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetWeatherBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetWeatherBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetWeatherBatch(ctx, req.(*WeatherBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_SearchCities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchCitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).SearchCities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_SearchCities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).SearchCities(ctx, req.(*SearchCitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WeatherService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WeatherServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WeatherService_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WeatherServiceServer).GetHistory(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WeatherService_ServiceDesc is the grpc.ServiceDesc for WeatherService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -119,7 +299,29 @@ var WeatherService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetWeather",
 			Handler:    _WeatherService_GetWeather_Handler,
 		},
+		{
+			MethodName: "GetForecast",
+			Handler:    _WeatherService_GetForecast_Handler,
+		},
+		{
+			MethodName: "GetWeatherBatch",
+			Handler:    _WeatherService_GetWeatherBatch_Handler,
+		},
+		{
+			MethodName: "SearchCities",
+			Handler:    _WeatherService_SearchCities_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _WeatherService_GetHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeWeather",
+			Handler:       _WeatherService_SubscribeWeather_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "weather_service.proto",
 }